@@ -200,6 +200,9 @@ func TestDatePickerAccessibility(t *testing.T) {
 	if !strings.Contains(html, `aria-label="Appointment date picker"`) {
 		t.Error("expected aria-label on modal")
 	}
+	if !strings.Contains(html, `aria-modal="true"`) {
+		t.Error("expected aria-modal on modal")
+	}
 }
 
 func TestDatePickerNoLabel(t *testing.T) {
@@ -218,3 +221,26 @@ func TestDatePickerNoLabel(t *testing.T) {
 		t.Error("should not render label when Label is empty")
 	}
 }
+
+func TestDatePickerNoInlineStyles(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := datepicker.DatePickerConfig{
+		ID:   "csp",
+		Name: "csp_date",
+	}
+	err := datepicker.DatePicker(cfg).Render(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html := buf.String()
+
+	if strings.Contains(html, "style=") {
+		t.Error("should not render inline styles")
+	}
+	if !strings.Contains(html, "ac-datepicker-back-hidden") {
+		t.Error("expected back button hidden on the initial year step")
+	}
+	if strings.Contains(html, "data-open") {
+		t.Error("overlay should start closed")
+	}
+}
@@ -0,0 +1,494 @@
+package form
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/a-h/templ"
+)
+
+// For and Bind read these struct tags:
+//
+//	form:"name"            the field's name; "-" skips it. Defaults to the Go
+//	                       name in snake_case: FirstName becomes first_name
+//	label:"First name"     defaults to the Go name as words
+//	type:"email"           any input type, or textarea, select, radio,
+//	                       checkbox or switch; inferred from the Go type
+//	options:"a:Label A|b"  choices for select, radio and []string fields;
+//	                       the label defaults to the value
+//	required:"true"
+//	placeholder, help, autocomplete, pattern, min, max, step, rows
+//
+// Supported field types are string, bool, the int, uint and float kinds,
+// []string and time.Time; embedded structs are flattened and anything else
+// is ignored. Strings default to text inputs (select with options), bools
+// to checkboxes, numbers to number inputs, time.Time to date inputs and
+// []string to a CheckboxGroup, which needs options.
+
+// For renders a form field for each field of the struct v points to, using
+// the existing inputs and the struct's current values. It renders only the
+// fields: wrap it in your own <form> with the submit button and CSRF token.
+func For(v any) templ.Component {
+	return ForWithErrors(v, nil)
+}
+
+// ForWithErrors is For with the error messages returned by Bind shown
+// under their fields.
+func ForWithErrors(v any, errs map[string]string) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		rv, fields, err := inspect(v)
+		if err != nil {
+			return err
+		}
+		for _, f := range fields {
+			c := structFieldInput(f, rv.FieldByIndex(f.index), errs[f.name])
+			if err := c.Render(ctx, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Bind decodes the request's form values into the struct dst points to,
+// converting them to the field types, and validates required fields,
+// options, email addresses and min/max. It returns an error message per
+// invalid field keyed by field name, like contact.FormData.Errors; the map
+// is empty when everything is valid. Strings are trimmed and, except in
+// textareas, stripped of line breaks. Every bound field is set, so a field
+// missing from the request ends up as its zero value.
+//
+// The body is parsed first. If that fails, for example because it is
+// malformed or larger than an http.MaxBytesReader allows, Bind returns the
+// error and leaves dst alone rather than reporting every field as missing.
+//
+// Bind panics if dst is not a pointer to a struct or its tags are invalid;
+// both are programming errors.
+func Bind(r *http.Request, dst any) (map[string]string, error) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		panic(fmt.Sprintf("form: Bind needs a pointer to a struct, got %T", dst))
+	}
+	rv, fields, err := inspect(dst)
+	if err != nil {
+		panic(err)
+	}
+	if err := parseForm(r); err != nil {
+		return nil, fmt.Errorf("form: parsing request: %w", err)
+	}
+	errs := map[string]string{}
+	for _, f := range fields {
+		if msg := f.decode(formValues(r, f.name), rv.FieldByIndex(f.index)); msg != "" {
+			errs[f.name] = msg
+		}
+	}
+	return errs, nil
+}
+
+// structField is a struct field as described by its tags.
+type structField struct {
+	index        []int
+	goType       reflect.Type
+	control      string // input type, or textarea, select, radio, checkbox, switch, checkboxes
+	name         string
+	label        string
+	placeholder  string
+	help         string
+	autocomplete string
+	pattern      string
+	min          string
+	max          string
+	step         string
+	rows         int
+	required     bool
+	options      []Choice
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	stringsType = reflect.TypeOf([]string(nil))
+
+	// fieldCache maps a struct type to its []structField or error.
+	fieldCache sync.Map
+)
+
+// timeLayouts are the value formats of the date and time input types.
+var timeLayouts = map[string]string{
+	"date":           "2006-01-02",
+	"datetime-local": "2006-01-02T15:04",
+	"month":          "2006-01",
+	"time":           "15:04",
+}
+
+// inspect returns the struct v holds or points to and its fields.
+func inspect(v any) (reflect.Value, []structField, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return rv, nil, fmt.Errorf("form: %T is not a struct", v)
+	}
+	t := rv.Type()
+	if cached, ok := fieldCache.Load(t); ok {
+		if err, ok := cached.(error); ok {
+			return rv, nil, err
+		}
+		return rv, cached.([]structField), nil
+	}
+	fields, err := structFields(t, nil)
+	if err != nil {
+		fieldCache.Store(t, err)
+		return rv, nil, err
+	}
+	fieldCache.Store(t, fields)
+	return rv, fields, nil
+}
+
+func structFields(t reflect.Type, index []int) ([]structField, error) {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("form")
+		if tag == "-" {
+			continue
+		}
+		idx := append(append([]int(nil), index...), i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct && tag == "" {
+			embedded, err := structFields(sf.Type, idx)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, embedded...)
+			continue
+		}
+		if !sf.IsExported() || !supported(sf.Type) {
+			continue
+		}
+		f, err := newStructField(sf, idx)
+		if err != nil {
+			return nil, fmt.Errorf("form: %s.%s: %w", t.Name(), sf.Name, err)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func supported(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return t == timeType || t == stringsType
+}
+
+func newStructField(sf reflect.StructField, index []int) (structField, error) {
+	words := splitWords(sf.Name)
+	f := structField{
+		index:        index,
+		goType:       sf.Type,
+		name:         sf.Tag.Get("form"),
+		label:        sf.Tag.Get("label"),
+		placeholder:  sf.Tag.Get("placeholder"),
+		help:         sf.Tag.Get("help"),
+		autocomplete: sf.Tag.Get("autocomplete"),
+		pattern:      sf.Tag.Get("pattern"),
+		min:          sf.Tag.Get("min"),
+		max:          sf.Tag.Get("max"),
+		step:         sf.Tag.Get("step"),
+		required:     sf.Tag.Get("required") == "true",
+		options:      parseOptions(sf.Tag.Get("options")),
+	}
+	if f.name == "" {
+		f.name = snakeCase(words)
+	}
+	if f.label == "" {
+		f.label = labelCase(words)
+	}
+	if rows := sf.Tag.Get("rows"); rows != "" {
+		n, err := strconv.Atoi(rows)
+		if err != nil {
+			return f, fmt.Errorf("invalid rows %q", rows)
+		}
+		f.rows = n
+	}
+	if err := f.numberBounds(); err != nil {
+		return f, err
+	}
+	control, err := controlFor(sf.Type, sf.Tag.Get("type"), len(f.options) > 0)
+	if err != nil {
+		return f, err
+	}
+	f.control = control
+	if control == "number" && f.step == "" && (sf.Type.Kind() == reflect.Float32 || sf.Type.Kind() == reflect.Float64) {
+		f.step = "any"
+	}
+	return f, nil
+}
+
+// controlFor checks the type tag against the Go type and fills in the
+// default.
+func controlFor(t reflect.Type, typ string, hasOptions bool) (string, error) {
+	switch {
+	case t == stringsType:
+		if !hasOptions || (typ != "" && typ != "checkbox") {
+			return "", errors.New("[]string fields need options and render as checkboxes")
+		}
+		return "checkboxes", nil
+	case t.Kind() == reflect.Bool:
+		if typ == "" {
+			typ = "checkbox"
+		}
+		if typ != "checkbox" && typ != "switch" {
+			return "", fmt.Errorf("type %q is not valid for a bool", typ)
+		}
+		return typ, nil
+	case t == timeType:
+		if typ == "" {
+			typ = "date"
+		}
+		if _, ok := timeLayouts[typ]; !ok {
+			return "", fmt.Errorf("type %q is not valid for a time.Time", typ)
+		}
+		return typ, nil
+	}
+	if hasOptions {
+		if typ == "" {
+			typ = "select"
+		}
+		if typ != "select" && typ != "radio" {
+			return "", fmt.Errorf("type %q can't take options", typ)
+		}
+		return typ, nil
+	}
+	switch typ {
+	case "":
+		if t.Kind() == reflect.String {
+			return "text", nil
+		}
+		return "number", nil
+	case "select", "radio", "checkbox", "switch", "checkboxes":
+		return "", fmt.Errorf("type %q needs a different field type or options", typ)
+	}
+	return typ, nil
+}
+
+// numberBounds checks that min and max are numbers on numeric fields.
+func (f structField) numberBounds() error {
+	if !isNumber(f.goType) {
+		return nil
+	}
+	for _, b := range []string{f.min, f.max} {
+		if b == "" {
+			continue
+		}
+		if x, err := strconv.ParseFloat(b, 64); err != nil || math.IsNaN(x) || math.IsInf(x, 0) {
+			return fmt.Errorf("invalid bound %q", b)
+		}
+	}
+	return nil
+}
+
+func isNumber(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// parseOptions reads "value:Label|value:Label"; a missing label is the
+// value.
+func parseOptions(tag string) []Choice {
+	if tag == "" {
+		return nil
+	}
+	var choices []Choice
+	for _, opt := range strings.Split(tag, "|") {
+		value, label, ok := strings.Cut(opt, ":")
+		if !ok {
+			label = value
+		}
+		choices = append(choices, Choice{Value: value, Label: label})
+	}
+	return choices
+}
+
+// format returns v as a form value. Zero numbers and times render empty so
+// a fresh struct gives an empty form rather than a row of zeros.
+func (f structField) format(v reflect.Value) string {
+	switch {
+	case f.goType == timeType:
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(timeLayouts[f.control])
+	case v.Kind() == reflect.String:
+		return v.String()
+	case v.IsZero():
+		return ""
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())
+	}
+	return ""
+}
+
+// decode sets v from the submitted values and returns an error message,
+// or "" if they are valid.
+func (f structField) decode(values []string, v reflect.Value) string {
+	switch f.control {
+	case "checkboxes":
+		checked := checkedAmong(values, f.options)
+		v.Set(reflect.ValueOf(checked))
+		if f.required && len(checked) == 0 {
+			return f.label + " is required"
+		}
+		return ""
+	case "checkbox", "switch":
+		on := len(values) > 0 && values[0] != ""
+		v.SetBool(on)
+		if f.required && !on {
+			return f.label + " is required"
+		}
+		return ""
+	}
+
+	raw := ""
+	if len(values) > 0 {
+		raw = strings.TrimSpace(values[0])
+	}
+	if f.control != "textarea" {
+		raw = strings.NewReplacer("\r", "", "\n", "").Replace(raw)
+	}
+	v.SetZero()
+	if raw == "" {
+		if f.required {
+			return f.label + " is required"
+		}
+		return ""
+	}
+	if len(f.options) > 0 && len(checkedAmong([]string{raw}, f.options)) == 0 {
+		return "Please choose a valid option"
+	}
+
+	switch {
+	case f.goType == timeType:
+		t, err := time.Parse(timeLayouts[f.control], raw)
+		if err != nil {
+			return f.label + " is not a valid " + strings.ReplaceAll(f.control, "-local", "")
+		}
+		v.Set(reflect.ValueOf(t))
+	case v.Kind() == reflect.String:
+		if f.control == "email" {
+			if _, err := mail.ParseAddress(raw); err != nil {
+				return "Please enter a valid email address"
+			}
+		}
+		v.SetString(raw)
+	default:
+		return f.decodeNumber(raw, v)
+	}
+	return ""
+}
+
+func (f structField) decodeNumber(raw string, v reflect.Value) string {
+	var n float64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return f.label + " must be a whole number"
+		}
+		v.SetInt(i)
+		n = float64(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return f.label + " must be a whole number"
+		}
+		v.SetUint(u)
+		n = float64(u)
+	default:
+		// ParseFloat also accepts NaN and Inf, which no number input sends
+		// and NaN would slip past the bounds below
+		x, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil || math.IsNaN(x) || math.IsInf(x, 0) {
+			return f.label + " must be a number"
+		}
+		v.SetFloat(x)
+		n = x
+	}
+	// The bounds were checked when the tags were read
+	if min, err := strconv.ParseFloat(f.min, 64); err == nil && n < min {
+		return f.label + " must be at least " + f.min
+	}
+	if max, err := strconv.ParseFloat(f.max, 64); err == nil && n > max {
+		return f.label + " must be at most " + f.max
+	}
+	return ""
+}
+
+// selectOptions marks the option matching value selected.
+func selectOptions(choices []Choice, value string) []SelectOption {
+	opts := make([]SelectOption, len(choices))
+	for i, c := range choices {
+		opts[i] = SelectOption{Value: c.Value, Label: c.Label, Selected: c.Value == value}
+	}
+	return opts
+}
+
+// splitWords splits a Go identifier at case changes, keeping acronyms
+// together: UserID is User, ID and HTTPPort is HTTP, Port.
+func splitWords(s string) []string {
+	r := []rune(s)
+	var words []string
+	start := 0
+	for i := 1; i < len(r); i++ {
+		prevLower := unicode.IsLower(r[i-1]) || unicode.IsDigit(r[i-1])
+		nextLower := i+1 < len(r) && unicode.IsLower(r[i+1])
+		if unicode.IsUpper(r[i]) && (prevLower || nextLower) {
+			words = append(words, string(r[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(r[start:]))
+}
+
+func snakeCase(words []string) string {
+	return strings.ToLower(strings.Join(words, "_"))
+}
+
+// labelCase turns words into sentence case, leaving acronyms alone.
+func labelCase(words []string) string {
+	out := make([]string, len(words))
+	for i, w := range words {
+		if i > 0 && strings.ToUpper(w) != w {
+			w = strings.ToLower(w)
+		}
+		out[i] = w
+	}
+	return strings.Join(out, " ")
+}
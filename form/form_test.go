@@ -3,8 +3,15 @@ package form_test
 import (
 	"bytes"
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/a-h/templ"
 
 	"github.com/AtomSites/atom-components/form"
 )
@@ -104,3 +111,430 @@ func TestSelect(t *testing.T) {
 		t.Error("expected selected attribute on Option B")
 	}
 }
+
+func TestTextInputWithProps(t *testing.T) {
+	var buf bytes.Buffer
+	err := form.TextInputWithProps(form.InputProps{
+		ID:           "qty",
+		Name:         "quantity",
+		Label:        "Quantity",
+		Type:         "number",
+		Value:        "3",
+		Help:         "Between 1 and 10",
+		Error:        "Too many",
+		Required:     true,
+		AutoComplete: "off",
+		InputMode:    "numeric",
+		Min:          "1",
+		Max:          "10",
+		Attrs:        templ.Attributes{"data-test": "x"},
+	}).Render(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html := buf.String()
+
+	for _, want := range []string{
+		`type="number"`,
+		`name="quantity"`,
+		` required`,
+		`autocomplete="off"`,
+		`inputmode="numeric"`,
+		`min="1"`,
+		`max="10"`,
+		`data-test="x"`,
+		`aria-invalid="true"`,
+		`aria-describedby="qty-help qty-error"`,
+		`<span id="qty-help" class="ac-help-text">Between 1 and 10</span>`,
+		`<span id="qty-error" class="ac-error-text">Too many</span>`,
+		`class="ac-required" aria-hidden="true"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s in %s", want, html)
+		}
+	}
+	for _, unwanted := range []string{"disabled", "readonly", "pattern=", "placeholder="} {
+		if strings.Contains(html, unwanted) {
+			t.Errorf("unexpected %s", unwanted)
+		}
+	}
+}
+
+func TestTextInputWithPropsDefaults(t *testing.T) {
+	var buf bytes.Buffer
+	err := form.TextInputWithProps(form.InputProps{Name: "city", Label: "City", Help: "Where you live"}).Render(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html := buf.String()
+
+	for _, want := range []string{`id="city"`, `for="city"`, `type="text"`, `aria-describedby="city-help"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s in %s", want, html)
+		}
+	}
+	if strings.Contains(html, "aria-invalid") || strings.Contains(html, "ac-required") {
+		t.Error("unexpected invalid or required markup")
+	}
+}
+
+func TestTextAreaAndSelectWithProps(t *testing.T) {
+	var buf bytes.Buffer
+	err := form.TextAreaWithProps(form.TextAreaProps{
+		ID:        "bio",
+		Name:      "bio",
+		Label:     "Bio",
+		Value:     "Hello",
+		ReadOnly:  true,
+		MaxLength: 200,
+		Error:     "Too short",
+	}).Render(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{` readonly`, `maxlength="200"`, `aria-describedby="bio-error"`, `>Hello</textarea>`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s in %s", want, html)
+		}
+	}
+	if strings.Contains(html, "rows=") {
+		t.Error("rows should be omitted when zero")
+	}
+
+	buf.Reset()
+	err = form.SelectWithProps(form.SelectProps{
+		ID:       "plan",
+		Name:     "plan",
+		Label:    "Plan",
+		Options:  []form.SelectOption{{Value: "free", Label: "Free"}},
+		Help:     "You can change it later",
+		Disabled: true,
+	}).Render(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html = buf.String()
+	for _, want := range []string{` disabled`, `aria-describedby="plan-help"`, `id="plan-help"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s in %s", want, html)
+		}
+	}
+}
+
+func TestCheckboxAndSwitch(t *testing.T) {
+	var buf bytes.Buffer
+	err := form.Checkbox(form.CheckboxProps{Name: "terms", Label: "I agree", Required: true, Error: "Please accept"}).Render(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{
+		`type="checkbox"`,
+		`id="terms"`,
+		`value="on"`,
+		`class="ac-checkbox ac-checkbox-error"`,
+		` required`,
+		`aria-describedby="terms-error"`,
+		`class="ac-required"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s in %s", want, html)
+		}
+	}
+	if strings.Contains(html, "checked") || strings.Contains(html, `role="switch"`) {
+		t.Error("unexpected checked or switch role")
+	}
+
+	buf.Reset()
+	err = form.Switch(form.CheckboxProps{ID: "n", Name: "notify", Label: "Notifications", Value: "yes", Checked: true}).Render(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html = buf.String()
+	for _, want := range []string{`role="switch"`, `class="ac-switch"`, `value="yes"`, ` checked`, `id="n"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s in %s", want, html)
+		}
+	}
+}
+
+func TestRadioGroup(t *testing.T) {
+	var buf bytes.Buffer
+	err := form.RadioGroup(form.GroupProps{
+		Name:   "plan",
+		Legend: "Plan",
+		Choices: []form.Choice{
+			{Value: "free", Label: "Free"},
+			{Value: "pro", Label: "Pro", Checked: true},
+			{Value: "team", Label: "Team", Disabled: true},
+		},
+		Help:     "Billed monthly",
+		Required: true,
+		Inline:   true,
+	}).Render(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{
+		`<fieldset id="plan" class="ac-form-group ac-fieldset ac-fieldset-inline"`,
+		`aria-describedby="plan-help"`,
+		`<legend class="ac-label ac-legend">`,
+		`type="radio" id="plan-0" name="plan" value="free"`,
+		`id="plan-1" name="plan" value="pro" class="ac-radio" checked`,
+		`value="team" class="ac-radio" disabled`,
+		`<span id="plan-help" class="ac-help-text">Billed monthly</span>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s in %s", want, html)
+		}
+	}
+	if n := strings.Count(html, " required"); n != 3 {
+		t.Errorf("expected every radio required, got %d", n)
+	}
+}
+
+func TestCheckboxGroup(t *testing.T) {
+	var buf bytes.Buffer
+	err := form.CheckboxGroup(form.GroupProps{
+		ID:       "topics",
+		Name:     "topic",
+		Legend:   "Topics",
+		Choices:  []form.Choice{{Value: "go", Label: "Go"}, {Value: "css", Label: "CSS"}},
+		Error:    "Pick at least one",
+		Required: true,
+		Disabled: true,
+	}).Render(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{
+		`class="ac-form-group ac-fieldset ac-fieldset-error" disabled`,
+		`aria-invalid="true"`,
+		`aria-describedby="topics-error"`,
+		`type="checkbox" id="topics-0" name="topic"`,
+		`class="ac-required"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s in %s", want, html)
+		}
+	}
+	if strings.Contains(html, " required") {
+		t.Error("checkbox groups should not mark each box required")
+	}
+}
+
+func TestCheckedValues(t *testing.T) {
+	choices := []form.Choice{
+		{Value: "go", Label: "Go"},
+		{Value: "css", Label: "CSS"},
+		{Value: "js", Label: "JS", Disabled: true},
+	}
+	body := url.Values{
+		"topic":  {"css", "go", "css", "js", "evil"},
+		"plan":   {"pro"},
+		"terms":  {"on"},
+		"single": {"css"},
+	}
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if got := form.CheckedValues(req, "topic", choices); !reflect.DeepEqual(got, []string{"go", "css"}) {
+		t.Errorf("CheckedValues = %v", got)
+	}
+	if got := form.CheckedValue(req, "single", choices); got != "css" {
+		t.Errorf("CheckedValue = %q", got)
+	}
+	if got := form.CheckedValue(req, "plan", choices); got != "" {
+		t.Errorf("CheckedValue should reject unknown values, got %q", got)
+	}
+	if !form.Checked(req, "terms") || form.Checked(req, "newsletter") {
+		t.Error("Checked mismatch")
+	}
+
+	checked := form.WithChecked(choices, "css")
+	if checked[0].Checked || !checked[1].Checked || choices[1].Checked {
+		t.Errorf("WithChecked = %+v, original %+v", checked, choices)
+	}
+}
+
+type audit struct {
+	CreatedBy string `form:"created_by" type:"hidden"`
+}
+
+type signup struct {
+	audit
+	Name       string    `required:"true" placeholder:"Your name" autocomplete:"name"`
+	Email      string    `type:"email" required:"true"`
+	Age        int       `min:"18" max:"120"`
+	Rating     float64   `help:"Out of 5" min:"0" max:"5"`
+	Plan       string    `options:":Choose...|free:Free|pro:Pro" required:"true"`
+	Billing    string    `type:"radio" options:"monthly:Monthly|yearly:Yearly"`
+	Topics     []string  `options:"go:Go|css:CSS"`
+	Bio        string    `type:"textarea" rows:"4"`
+	Newsletter bool      `type:"switch" label:"Send me news"`
+	StartDate  time.Time `label:"Start"`
+	UserID     string    `form:"-"`
+	internal   string
+}
+
+func TestFor(t *testing.T) {
+	v := signup{
+		audit:      audit{CreatedBy: "admin"},
+		Name:       "Ada",
+		Plan:       "pro",
+		Topics:     []string{"css"},
+		Newsletter: true,
+		StartDate:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	var buf bytes.Buffer
+	err := form.ForWithErrors(&v, map[string]string{"email": "Email is required"}).Render(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{
+		`<input type="hidden" name="created_by" value="admin">`,
+		`type="text" id="name" name="name" class="ac-input" placeholder="Your name" value="Ada" required autocomplete="name"`,
+		`type="email" id="email"`,
+		`<span id="email-error" class="ac-error-text">Email is required</span>`,
+		`type="number" id="age" name="age" class="ac-input" value="" min="18" max="120"`,
+		`step="any"`,
+		`aria-describedby="rating-help"`,
+		`<option value="pro" selected>Pro</option>`,
+		`<select id="plan" name="plan" class="ac-select" required>`,
+		`type="radio" id="billing-0" name="billing" value="monthly"`,
+		`type="checkbox" id="topics-1" name="topics" value="css" class="ac-checkbox" checked`,
+		`<textarea id="bio" name="bio" class="ac-textarea" rows="4"`,
+		`role="switch" checked`,
+		`Send me news`,
+		`type="date" id="start_date" name="start_date" class="ac-input" value="2024-03-01"`,
+		`<label class="ac-label" for="start_date">Start`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s in %s", want, html)
+		}
+	}
+	if strings.Contains(html, "user_id") || strings.Contains(html, "internal") {
+		t.Error("skipped fields should not render")
+	}
+
+	if err := form.For(42).Render(context.Background(), &buf); err == nil {
+		t.Error("expected an error for a non-struct")
+	}
+}
+
+func TestBind(t *testing.T) {
+	body := url.Values{
+		"created_by": {"admin"},
+		"name":       {"  Ada\r\nLovelace "},
+		"email":      {"ada@example.com"},
+		"age":        {"36"},
+		"rating":     {"4.5"},
+		"plan":       {"free"},
+		"billing":    {"yearly"},
+		"topics":     {"go", "evil"},
+		"bio":        {"Line one\nLine two"},
+		"newsletter": {"on"},
+		"start_date": {"2024-03-01"},
+		"user_id":    {"42"},
+	}
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	v := signup{UserID: "7"}
+	errs, err := form.Bind(req, &v)
+	if err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	want := signup{
+		audit:      audit{CreatedBy: "admin"},
+		Name:       "AdaLovelace",
+		Email:      "ada@example.com",
+		Age:        36,
+		Rating:     4.5,
+		Plan:       "free",
+		Billing:    "yearly",
+		Topics:     []string{"go"},
+		Bio:        "Line one\nLine two",
+		Newsletter: true,
+		StartDate:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		UserID:     "7",
+	}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("Bind = %+v, want %+v", v, want)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	body := url.Values{
+		"email":      {"not an email"},
+		"age":        {"12"},
+		"rating":     {"lots"},
+		"plan":       {"enterprise"},
+		"start_date": {"yesterday"},
+	}
+	req := httptest.NewRequest("POST", "/?newsletter=on", strings.NewReader(body.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	v := signup{Name: "stale", Age: 99}
+	errs, err := form.Bind(req, &v)
+	if err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	want := map[string]string{
+		"name":       "Name is required",
+		"email":      "Please enter a valid email address",
+		"age":        "Age must be at least 18",
+		"rating":     "Rating must be a number",
+		"plan":       "Please choose a valid option",
+		"start_date": "Start is not a valid date",
+	}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("Bind errors = %v, want %v", errs, want)
+	}
+	if v.Name != "" || !v.Newsletter {
+		t.Errorf("fields should be reset from the request, got %+v", v)
+	}
+
+	for _, bad := range []string{"NaN", "nan", "Inf", "-Inf", "+Infinity"} {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(url.Values{"rating": {bad}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		var v signup
+		if errs, _ := form.Bind(req, &v); errs["rating"] != "Rating must be a number" || v.Rating != 0 {
+			t.Errorf("rating=%s: got %v, Rating = %v", bad, errs["rating"], v.Rating)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Bind to panic on a non-pointer")
+		}
+	}()
+	_, _ = form.Bind(req, v)
+}
+
+func TestBindParseError(t *testing.T) {
+	for name, req := range map[string]*http.Request{
+		"malformed": httptest.NewRequest("POST", "/", strings.NewReader("name=%zz")),
+		"too large": httptest.NewRequest("POST", "/", strings.NewReader("name=Ada&bio="+strings.Repeat("x", 1024))),
+	} {
+		t.Run(name, func(t *testing.T) {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Body = http.MaxBytesReader(httptest.NewRecorder(), req.Body, 512)
+			v := signup{Name: "kept"}
+			errs, err := form.Bind(req, &v)
+			if err == nil {
+				t.Fatalf("expected a parse error, got errors %v", errs)
+			}
+			if errs != nil || v.Name != "kept" {
+				t.Errorf("fields should be left alone, got %v and %+v", errs, v)
+			}
+		})
+	}
+}
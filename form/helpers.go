@@ -1,7 +1,58 @@
 package form
 
-import "strconv"
+import (
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+)
 
 func intToString(i int) string {
 	return strconv.Itoa(i)
 }
+
+// fieldID is the control's id: the explicit one, or the name.
+func fieldID(id, name string) string {
+	if id != "" {
+		return id
+	}
+	return name
+}
+
+// optionID is the id of the i-th option of a group. Values aren't used
+// since they may not be valid in an id.
+func optionID(id string, i int) string {
+	return id + "-" + strconv.Itoa(i)
+}
+
+func checkboxValue(v string) string {
+	if v == "" {
+		return "on"
+	}
+	return v
+}
+
+func inputType(t string) string {
+	if t == "" {
+		return "text"
+	}
+	return t
+}
+
+// describedBy links a control to its help text and error message, and
+// marks it invalid when there is an error.
+func describedBy(id, help, errMsg string) templ.Attributes {
+	attrs := templ.Attributes{}
+	var ids []string
+	if help != "" {
+		ids = append(ids, id+"-help")
+	}
+	if errMsg != "" {
+		ids = append(ids, id+"-error")
+		attrs["aria-invalid"] = "true"
+	}
+	if len(ids) > 0 {
+		attrs["aria-describedby"] = strings.Join(ids, " ")
+	}
+	return attrs
+}
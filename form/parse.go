@@ -0,0 +1,86 @@
+package form
+
+import (
+	"errors"
+	"net/http"
+)
+
+// maxMemory is how much of a multipart body is kept in memory, the same as
+// Request.FormValue uses.
+const maxMemory = 32 << 20
+
+// Checked reports whether the Checkbox or Switch name was submitted
+// checked. Browsers leave unchecked boxes out of the form entirely.
+func Checked(r *http.Request, name string) bool {
+	return r.FormValue(name) != ""
+}
+
+// CheckedValues returns the values of a CheckboxGroup that were submitted,
+// in the order of choices. Values that aren't among the enabled choices are
+// ignored, as are duplicates, so the result can be trusted as far as choices
+// can.
+func CheckedValues(r *http.Request, name string, choices []Choice) []string {
+	return checkedAmong(formValues(r, name), choices)
+}
+
+// CheckedValue returns the value of a RadioGroup that was submitted, or ""
+// if none was or it isn't one of the enabled choices.
+func CheckedValue(r *http.Request, name string, choices []Choice) string {
+	values := CheckedValues(r, name, choices)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// WithChecked returns a copy of choices with exactly the given values
+// checked, for rendering a group again with what was submitted.
+func WithChecked(choices []Choice, values ...string) []Choice {
+	checked := make(map[string]bool, len(values))
+	for _, v := range values {
+		checked[v] = true
+	}
+	out := make([]Choice, len(choices))
+	for i, c := range choices {
+		c.Checked = checked[c.Value]
+		out[i] = c
+	}
+	return out
+}
+
+func checkedAmong(values []string, choices []Choice) []string {
+	submitted := map[string]bool{}
+	for _, v := range values {
+		submitted[v] = true
+	}
+	var checked []string
+	for _, c := range choices {
+		if !c.Disabled && submitted[c.Value] {
+			checked = append(checked, c.Value)
+			submitted[c.Value] = false
+		}
+	}
+	return checked
+}
+
+// formValues returns every submitted value of name. FormValue parses the
+// query and body first, the same way a single value would be read.
+func formValues(r *http.Request, name string) []string {
+	r.FormValue(name)
+	return r.Form[name]
+}
+
+// parseForm parses the query and body like FormValue does, but reports
+// the errors FormValue drops.
+func parseForm(r *http.Request) error {
+	// ParseMultipartForm would run ParseForm too, but drops its error when
+	// the body isn't multipart
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	err := r.ParseMultipartForm(maxMemory)
+	if errors.Is(err, http.ErrNotMultipart) {
+		return nil
+	}
+	return err
+}
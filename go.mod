@@ -2,4 +2,7 @@ module github.com/AtomSites/atom-components
 
 go 1.25.0
 
-require github.com/a-h/templ v0.3.977 // indirect
+require (
+	github.com/a-h/templ v0.3.977
+	github.com/andybalholm/brotli v1.1.0
+)
@@ -0,0 +1,196 @@
+// Command assetgen builds the combined CSS and JS bundles from the
+// per-component fragments, then writes minified, gzip and brotli variants of
+// every file next to the original. It is run by go generate in ./static:
+//
+//	css/components/*.css    -> css/atom-components.css
+//	css/atom-components.css -> css/atom-components.min.css{,.gz,.br}
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+
+	"github.com/AtomSites/atom-components/static"
+)
+
+const cssHeader = `/* ============================================================
+   Atom Components — ac-* prefixed styles
+   All colors/effects reference the host project's CSS variables.
+   Generated by go generate from css/components/*.css. DO NOT EDIT.
+   ============================================================ */
+`
+
+const jsHeader = `// Atom Components — generated by go generate from js/components/*.js.
+// DO NOT EDIT.
+`
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "assetgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	if err := combine(static.CSS, cssHeader, static.Bundle.CSS); err != nil {
+		return err
+	}
+	if err := combine(static.JS, jsHeader, static.Bundle.JS); err != nil {
+		return err
+	}
+	patterns := []string{"css/*.css", "css/components/*.css", "js/*.js", "js/components/*.js"}
+	for _, pattern := range patterns {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			if strings.Contains(filepath.Base(f), ".min.") {
+				continue
+			}
+			if err := generate(f); err != nil {
+				return fmt.Errorf("%s: %w", f, err)
+			}
+		}
+	}
+	return nil
+}
+
+// combine concatenates each bundle's fragment, in static.Bundles order, into
+// the combined file name.
+func combine(name, header string, path func(static.Bundle) string) error {
+	var out bytes.Buffer
+	out.WriteString(header)
+	for _, b := range static.Bundles() {
+		p := path(b)
+		if p == "" {
+			continue
+		}
+		src, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		out.WriteByte('\n')
+		out.Write(src)
+	}
+	return os.WriteFile(name, out.Bytes(), 0o644)
+}
+
+func generate(name string) error {
+	src, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	var minified []byte
+	switch filepath.Ext(name) {
+	case ".css":
+		minified = minifyCSS(src)
+	case ".js":
+		minified = minifyJS(src)
+	}
+	ext := filepath.Ext(name)
+	out := strings.TrimSuffix(name, ext) + ".min" + ext
+	if err := os.WriteFile(out, minified, 0o644); err != nil {
+		return err
+	}
+
+	var gz bytes.Buffer
+	gw, err := gzip.NewWriterLevel(&gz, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := gw.Write(minified); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	if err := os.WriteFile(out+".gz", gz.Bytes(), 0o644); err != nil {
+		return err
+	}
+
+	var br bytes.Buffer
+	bw := brotli.NewWriterLevel(&br, brotli.BestCompression)
+	if _, err := bw.Write(minified); err != nil {
+		return err
+	}
+	if err := bw.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(out+".br", br.Bytes(), 0o644)
+}
+
+// minifyCSS strips comments and collapses whitespace outside of quoted
+// strings. Spaces are only dropped next to punctuation where they can never
+// be significant, so descendant selectors like ".a :hover" survive.
+func minifyCSS(src []byte) []byte {
+	var out bytes.Buffer
+	space := false
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := bytes.Index(src[i+2:], []byte("*/"))
+			if end < 0 {
+				return out.Bytes()
+			}
+			i += end + 3
+		case c == '"' || c == '\'':
+			if space {
+				out.WriteByte(' ')
+				space = false
+			}
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			out.Write(src[i:min(j+1, len(src))])
+			i = j
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+		default:
+			if space && out.Len() > 0 && !strings.ContainsRune("{};,>", rune(c)) &&
+				!strings.ContainsRune("{};,>:", rune(lastByte(&out))) {
+				out.WriteByte(' ')
+			}
+			space = false
+			if c == '}' && lastByte(&out) == ';' {
+				out.Truncate(out.Len() - 1)
+			}
+			out.WriteByte(c)
+		}
+	}
+	return out.Bytes()
+}
+
+// minifyJS drops indentation, blank lines and whole-line // comments. Line
+// breaks are kept so automatic semicolon insertion behaves exactly as in the
+// source.
+func minifyJS(src []byte) []byte {
+	var out bytes.Buffer
+	for _, line := range strings.Split(string(src), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	return out.Bytes()
+}
+
+func lastByte(b *bytes.Buffer) byte {
+	if b.Len() == 0 {
+		return 0
+	}
+	return b.Bytes()[b.Len()-1]
+}
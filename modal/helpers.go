@@ -0,0 +1,82 @@
+package modal
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/a-h/templ"
+)
+
+// FragmentHeader is sent with every request a remote modal makes, so an
+// endpoint can serve both a full page and the bare fragment.
+const FragmentHeader = "X-AC-Fragment"
+
+// IsFragment reports whether r was made by a remote modal.
+func IsFragment(r *http.Request) bool {
+	return r.Header.Get(FragmentHeader) != ""
+}
+
+// WriteFragment renders c on its own, without a page layout, as the response
+// to a remote modal. The component is rendered before anything is written,
+// so on error the caller can still send an error status.
+func WriteFragment(w http.ResponseWriter, r *http.Request, c templ.Component) error {
+	var buf bytes.Buffer
+	if err := c.Render(r.Context(), &buf); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Add("Vary", FragmentHeader)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func withFooter(cfg Config, footer templ.Component) Config {
+	cfg.Footer = footer
+	return cfg
+}
+
+func withURL(cfg Config, url string) Config {
+	cfg.URL = url
+	return cfg
+}
+
+func drawerSide(s Side) Side {
+	switch s {
+	case SideLeft, SideBottom:
+		return s
+	}
+	return SideRight
+}
+
+// drawerSizeClass is sizeClass for drawers, which size along their own axis.
+func drawerSizeClass(s Size) templ.KeyValue[string, bool] {
+	switch s {
+	case SizeSM, SizeLG, SizeFullscreen:
+		return templ.KV("ac-drawer-"+string(s), true)
+	}
+	return templ.KV("", false)
+}
+
+// confirmModal is the modal a Confirm dialog renders as.
+func confirmModal(cfg ConfirmConfig) Config {
+	m := NewConfig(cfg.ID, orDefault(cfg.Title, "Are you sure?"))
+	m.Size = SizeSM
+	m.Footer = confirmFooter(cfg)
+	return m
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// sizeClass returns the size modifier class; the default size needs none.
+func sizeClass(s Size) templ.KeyValue[string, bool] {
+	switch s {
+	case SizeSM, SizeLG, SizeFullscreen:
+		return templ.KV("ac-modal-"+string(s), true)
+	}
+	return templ.KV("", false)
+}
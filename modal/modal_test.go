@@ -3,7 +3,10 @@ package modal_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -45,6 +48,12 @@ func TestModal(t *testing.T) {
 	if !strings.Contains(html, `role="dialog"`) {
 		t.Error("expected dialog role")
 	}
+	if !strings.Contains(html, `aria-modal="true"`) {
+		t.Error("expected aria-modal")
+	}
+	if !strings.Contains(html, `tabindex="-1"`) {
+		t.Error("expected focusable dialog fallback")
+	}
 }
 
 func TestModalWithFooter(t *testing.T) {
@@ -74,3 +83,317 @@ func TestModalWithFooter(t *testing.T) {
 		t.Error("expected body content")
 	}
 }
+
+func renderModal(t *testing.T, c templ.Component) string {
+	t.Helper()
+	var buf bytes.Buffer
+	err := c.Render(templ.WithChildren(context.Background(), templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, e := io.WriteString(w, "<p>Body</p>")
+		return e
+	})), &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestModalWithConfigDefaults(t *testing.T) {
+	html := renderModal(t, modal.ModalWithConfig(modal.NewConfig("edit", "Edit")))
+
+	if !strings.Contains(html, `class="ac-modal"`) {
+		t.Error("expected default size without modifier class")
+	}
+	if !strings.Contains(html, "data-modal-close") {
+		t.Error("expected close button")
+	}
+	for _, attr := range []string{"data-modal-persistent", "data-modal-no-overlay-close", "data-open"} {
+		if strings.Contains(html, attr) {
+			t.Errorf("unexpected %s", attr)
+		}
+	}
+	if strings.Contains(html, "ac-modal-footer") {
+		t.Error("unexpected footer without Footer")
+	}
+}
+
+func TestModalWithConfigSizes(t *testing.T) {
+	for _, size := range []modal.Size{modal.SizeSM, modal.SizeLG, modal.SizeFullscreen} {
+		cfg := modal.NewConfig("m", "Sized")
+		cfg.Size = size
+		html := renderModal(t, modal.ModalWithConfig(cfg))
+		if !strings.Contains(html, `class="ac-modal ac-modal-`+string(size)+`"`) {
+			t.Errorf("expected ac-modal-%s class", size)
+		}
+	}
+}
+
+func TestModalWithConfigNonDismissible(t *testing.T) {
+	cfg := modal.NewConfig("terms", "Accept Terms")
+	cfg.Dismissible = false
+	html := renderModal(t, modal.ModalWithConfig(cfg))
+
+	if !strings.Contains(html, "data-modal-persistent") {
+		t.Error("expected data-modal-persistent")
+	}
+	if strings.Contains(html, "ac-modal-close") {
+		t.Error("non-dismissible modal should not render a close button")
+	}
+}
+
+func TestModalWithConfigOptions(t *testing.T) {
+	footer := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, e := io.WriteString(w, `<button data-modal-close>Done</button>`)
+		return e
+	})
+	cfg := modal.NewConfig("welcome", "Welcome")
+	cfg.CloseOnOverlay = false
+	cfg.OpenOnLoad = true
+	cfg.HideCloseButton = true
+	cfg.Footer = footer
+	html := renderModal(t, modal.ModalWithConfig(cfg))
+
+	if !strings.Contains(html, "data-modal-no-overlay-close") {
+		t.Error("expected data-modal-no-overlay-close")
+	}
+	if !strings.Contains(html, "data-open") {
+		t.Error("expected modal rendered open")
+	}
+	if strings.Contains(html, "ac-modal-close") {
+		t.Error("expected header close button hidden")
+	}
+	if !strings.Contains(html, "ac-modal-footer") || !strings.Contains(html, ">Done</button>") {
+		t.Error("expected footer content")
+	}
+}
+
+func TestModalWithConfigNative(t *testing.T) {
+	cfg := modal.NewConfig("native", "Native Dialog")
+	cfg.Native = true
+	cfg.Size = modal.SizeSM
+	html := renderModal(t, modal.ModalWithConfig(cfg))
+
+	if !strings.Contains(html, `<dialog id="native"`) {
+		t.Error("expected native dialog element")
+	}
+	if !strings.Contains(html, `class="ac-modal ac-modal-native ac-modal-sm"`) {
+		t.Error("expected modal, native and size classes on the dialog")
+	}
+	if strings.Contains(html, "ac-modal-overlay") {
+		t.Error("native dialog should not render the overlay div")
+	}
+	if !strings.Contains(html, `<form method="dialog"`) {
+		t.Error("expected method=dialog form for the close button")
+	}
+	if !strings.Contains(html, "data-modal-close") {
+		t.Error("expected data-modal-close hook on the close button")
+	}
+	if strings.Contains(html, " open") {
+		t.Error("dialog should start closed")
+	}
+	if !strings.Contains(html, "<p>Body</p>") {
+		t.Error("expected children content")
+	}
+
+	cfg.OpenOnLoad = true
+	cfg.Dismissible = false
+	html = renderModal(t, modal.ModalWithConfig(cfg))
+	if !strings.Contains(html, " open") {
+		t.Error("expected open attribute")
+	}
+	if !strings.Contains(html, "data-modal-persistent") {
+		t.Error("expected data-modal-persistent")
+	}
+	if strings.Contains(html, `method="dialog"`) {
+		t.Error("non-dismissible dialog should not render a close form")
+	}
+}
+
+func TestRemote(t *testing.T) {
+	html := renderModal(t, modal.Remote("edit-row", "Edit Row", "/rows/7/edit"))
+
+	if !strings.Contains(html, `data-modal-url="/rows/7/edit"`) {
+		t.Error("expected data-modal-url")
+	}
+	if !strings.Contains(html, `class="ac-modal-body"`) {
+		t.Error("expected body for the fragment to be swapped into")
+	}
+	if !strings.Contains(html, "Edit Row") {
+		t.Error("expected title")
+	}
+
+	html = renderModal(t, modal.Modal("plain", "Plain"))
+	if strings.Contains(html, "data-modal-url") {
+		t.Error("unexpected data-modal-url without URL")
+	}
+}
+
+func TestWriteFragment(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/rows/7/edit", nil)
+	if modal.IsFragment(r) {
+		t.Error("plain request reported as fragment")
+	}
+	r.Header.Set(modal.FragmentHeader, "modal")
+	if !modal.IsFragment(r) {
+		t.Error("expected fragment request")
+	}
+
+	w := httptest.NewRecorder()
+	frag := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, e := io.WriteString(w, `<form id="row-7"></form>`)
+		return e
+	})
+	if err := modal.WriteFragment(w, r, frag); err != nil {
+		t.Fatalf("WriteFragment: %v", err)
+	}
+	if got := w.Body.String(); got != `<form id="row-7"></form>` {
+		t.Errorf("body = %q, want the bare fragment", got)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if v := w.Header().Get("Vary"); v != modal.FragmentHeader {
+		t.Errorf("Vary = %q", v)
+	}
+
+	w = httptest.NewRecorder()
+	broken := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if _, e := io.WriteString(w, "<p>partial"); e != nil {
+			return e
+		}
+		return errors.New("boom")
+	})
+	if err := modal.WriteFragment(w, r, broken); err == nil {
+		t.Error("expected render error")
+	}
+	if w.Body.Len() != 0 {
+		t.Error("nothing should be written when rendering fails")
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	html := renderModal(t, modal.Confirm(modal.ConfirmConfig{
+		ID:           "delete-project",
+		Message:      "This deletes the project and all its files.",
+		ConfirmLabel: "Delete",
+		Danger:       true,
+		Action:       "/projects/7/delete",
+		CSRFToken:    "tok123",
+	}))
+
+	if !strings.Contains(html, `id="delete-project"`) {
+		t.Error("expected dialog id")
+	}
+	if !strings.Contains(html, "Are you sure?") {
+		t.Error("expected default title")
+	}
+	if !strings.Contains(html, "This deletes the project and all its files.") {
+		t.Error("expected message")
+	}
+	if !strings.Contains(html, "ac-modal-sm") {
+		t.Error("expected small dialog")
+	}
+	if !strings.Contains(html, `method="POST" action="/projects/7/delete"`) {
+		t.Error("expected POST form to the action")
+	}
+	if !strings.Contains(html, `name="csrf_token" value="tok123"`) {
+		t.Error("expected csrf token")
+	}
+	if !strings.Contains(html, `type="submit" class="ac-confirm-btn ac-confirm-btn-danger" data-confirm-ok`) {
+		t.Error("expected danger submit button")
+	}
+	if !strings.Contains(html, "Delete") || !strings.Contains(html, "Cancel") {
+		t.Error("expected button labels")
+	}
+	if !strings.Contains(html, "data-confirm-cancel") {
+		t.Error("expected cancel button")
+	}
+}
+
+func TestConfirmWithoutAction(t *testing.T) {
+	html := renderModal(t, modal.Confirm(modal.ConfirmConfig{
+		ID:          "discard",
+		Title:       "Discard draft?",
+		CancelLabel: "Keep editing",
+	}))
+
+	if strings.Contains(html, "<form") {
+		t.Error("unexpected form without Action")
+	}
+	if strings.Contains(html, "csrf_token") {
+		t.Error("unexpected csrf token")
+	}
+	if !strings.Contains(html, `type="button" class="ac-confirm-btn ac-confirm-btn-primary" data-confirm-ok`) {
+		t.Error("expected primary button that doesn't submit")
+	}
+	if !strings.Contains(html, "Discard draft?") || !strings.Contains(html, "Keep editing") || !strings.Contains(html, "Confirm") {
+		t.Error("expected custom title, cancel label and default confirm label")
+	}
+}
+
+func TestDrawer(t *testing.T) {
+	cfg := modal.NewConfig("filters", "Filters")
+	html := renderModal(t, modal.Drawer(cfg, ""))
+
+	if !strings.Contains(html, `class="ac-modal-overlay ac-drawer-overlay"`) {
+		t.Error("expected drawer overlay sharing the modal overlay class")
+	}
+	if !strings.Contains(html, `data-drawer-side="right"`) {
+		t.Error("expected right side by default")
+	}
+	if !strings.Contains(html, `class="ac-modal ac-drawer ac-drawer-right"`) {
+		t.Error("expected drawer panel classes")
+	}
+	if !strings.Contains(html, `role="dialog"`) || !strings.Contains(html, `aria-labelledby="filters-title"`) {
+		t.Error("expected dialog semantics")
+	}
+	if !strings.Contains(html, "data-modal-close") {
+		t.Error("expected close button")
+	}
+	if !strings.Contains(html, "<p>Body</p>") {
+		t.Error("expected children content")
+	}
+}
+
+func TestDrawerSidesAndSizes(t *testing.T) {
+	cfg := modal.NewConfig("nav", "Menu")
+	cfg.Size = modal.SizeLG
+	cfg.Dismissible = false
+	html := renderModal(t, modal.Drawer(cfg, modal.SideLeft))
+	if !strings.Contains(html, `data-drawer-side="left"`) || !strings.Contains(html, "ac-drawer-left ac-drawer-lg") {
+		t.Error("expected left large drawer")
+	}
+	if strings.Contains(html, "ac-modal-lg") {
+		t.Error("drawer should not use the modal width classes")
+	}
+	if !strings.Contains(html, "data-modal-persistent") {
+		t.Error("expected data-modal-persistent")
+	}
+
+	html = renderModal(t, modal.Drawer(modal.NewConfig("sheet", "Details"), modal.SideBottom))
+	if !strings.Contains(html, `data-drawer-side="bottom"`) || !strings.Contains(html, "ac-drawer-bottom") {
+		t.Error("expected bottom sheet")
+	}
+}
+
+func TestModalWithConfigDeepLink(t *testing.T) {
+	cfg := modal.NewConfig("compare", "Compare Plans")
+	if html := renderModal(t, modal.ModalWithConfig(cfg)); strings.Contains(html, "data-modal-deeplink") {
+		t.Error("deep linking should be opt-in")
+	}
+
+	cfg.DeepLink = true
+	for name, c := range map[string]templ.Component{
+		"overlay": modal.ModalWithConfig(cfg),
+		"native":  modal.ModalWithConfig(withNative(cfg)),
+		"drawer":  modal.Drawer(cfg, modal.SideRight),
+	} {
+		if html := renderModal(t, c); !strings.Contains(html, "data-modal-deeplink") {
+			t.Errorf("%s: expected data-modal-deeplink", name)
+		}
+	}
+}
+
+func withNative(cfg modal.Config) modal.Config {
+	cfg.Native = true
+	return cfg
+}
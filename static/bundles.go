@@ -0,0 +1,188 @@
+package static
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/a-h/templ"
+)
+
+// Bundle names a per-component CSS/JS fragment. Fragments live in
+// css/components and js/components; go generate concatenates them, in
+// Bundles order, into the combined CSS and JS files.
+type Bundle string
+
+const (
+	Form       Bundle = "form"
+	Contact    Bundle = "contact"
+	Modal      Bundle = "modal"
+	Toast      Bundle = "toast"
+	Card       Bundle = "card"
+	DatePicker Bundle = "datepicker"
+	Theme      Bundle = "theme"
+
+	// core holds the JS helpers shared by the other bundles.
+	core Bundle = "core"
+)
+
+type bundleInfo struct {
+	css  bool
+	js   bool
+	deps []Bundle
+}
+
+// bundleOrder is the canonical order; dependencies come first.
+var bundleOrder = []Bundle{core, Form, Contact, Modal, Toast, Card, DatePicker, Theme}
+
+var bundleInfos = map[Bundle]bundleInfo{
+	core:       {js: true},
+	Form:       {css: true},
+	Contact:    {css: true, deps: []Bundle{Form}},
+	Modal:      {css: true, js: true, deps: []Bundle{core}},
+	Toast:      {css: true, js: true},
+	Card:       {css: true},
+	DatePicker: {css: true, js: true, deps: []Bundle{core, Form, Modal}},
+	Theme:      {css: true, js: true},
+}
+
+// Bundles returns every bundle in the order they are concatenated.
+func Bundles() []Bundle {
+	return append([]Bundle(nil), bundleOrder...)
+}
+
+// CSS returns the path of the bundle's stylesheet in Assets, or "" if it
+// has none.
+func (b Bundle) CSS() string {
+	if !bundleInfos[b].css {
+		return ""
+	}
+	return "css/components/" + string(b) + ".css"
+}
+
+// JS returns the path of the bundle's script in Assets, or "" if it has
+// none.
+func (b Bundle) JS() string {
+	if !bundleInfos[b].js {
+		return ""
+	}
+	return "js/components/" + string(b) + ".js"
+}
+
+// Registry records which bundles a page uses. Components register
+// themselves while rendering; Styles, InlineStyles and Scripts then emit
+// only those bundles, each at most once per request.
+type Registry struct {
+	mu      sync.Mutex
+	used    map[Bundle]bool
+	emitted map[string]bool // asset names already written
+}
+
+type registryKey struct{}
+
+// WithRegistry returns a context carrying a fresh Registry. Call it once per
+// request before rendering. Without a registry, Styles and Scripts fall back
+// to the combined bundles.
+func WithRegistry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, registryKey{}, &Registry{
+		used:    map[Bundle]bool{},
+		emitted: map[string]bool{},
+	})
+}
+
+func registryFrom(ctx context.Context) *Registry {
+	r, _ := ctx.Value(registryKey{}).(*Registry)
+	return r
+}
+
+// Require marks bundles (and their dependencies) as used by the current
+// request. Handlers can call it before rendering so that a Styles tag in
+// <head> includes components rendered later in the body.
+func Require(ctx context.Context, bundles ...Bundle) {
+	r := registryFrom(ctx)
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range bundles {
+		r.require(b)
+	}
+}
+
+func (r *Registry) require(b Bundle) {
+	if r.used[b] {
+		return
+	}
+	r.used[b] = true
+	for _, d := range bundleInfos[b].deps {
+		r.require(d)
+	}
+}
+
+// Use is the render-time form of Require. Components include it in their
+// templates; it writes nothing.
+func Use(bundles ...Bundle) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, _ io.Writer) error {
+		Require(ctx, bundles...)
+		return nil
+	})
+}
+
+// pending returns the asset names of the used bundles of one kind that have
+// not been emitted yet and marks them emitted. Without a registry it returns
+// the combined bundle once.
+func pending(ctx context.Context, path func(Bundle) string, combined string) []string {
+	r := registryFrom(ctx)
+	if r == nil {
+		return []string{combined}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var names []string
+	for _, b := range bundleOrder {
+		name := path(b)
+		if !r.used[b] || name == "" || r.emitted[name] {
+			continue
+		}
+		r.emitted[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+func pendingCSS(ctx context.Context) []string {
+	return pending(ctx, Bundle.CSS, CSS)
+}
+
+func pendingJS(ctx context.Context) []string {
+	return pending(ctx, Bundle.JS, JS)
+}
+
+// InlineStyles is like Styles but inlines the minified CSS in a <style>
+// element, for critical above-the-fold components. The CSP nonce, if any,
+// is added to the element.
+func InlineStyles() templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		names := pendingCSS(ctx)
+		if len(names) == 0 {
+			return nil
+		}
+		open := "<style>"
+		if nonce := templ.GetNonce(ctx); nonce != "" {
+			open = `<style nonce="` + templ.EscapeString(nonce) + `">`
+		}
+		if _, err := io.WriteString(w, open); err != nil {
+			return err
+		}
+		for _, name := range names {
+			if a, ok := assets[name]; ok {
+				if _, err := w.Write(a.body); err != nil {
+					return err
+				}
+			}
+		}
+		_, err := io.WriteString(w, "</style>")
+		return err
+	})
+}
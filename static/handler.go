@@ -0,0 +1,188 @@
+package static
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Names of the embedded bundles, relative to Assets.
+const (
+	CSS = "css/atom-components.css"
+	JS  = "js/atom-components.js"
+)
+
+// Prefix is the URL path Handler is mounted under. URL prepends it to the
+// fingerprinted asset path, so set it once at startup if you mount the
+// handler somewhere other than /static/ac/.
+var Prefix = "/static/ac/"
+
+// asset is an embedded file with its fingerprint precomputed. When go
+// generate has produced minified and precompressed variants, body holds the
+// minified bytes and gzip/br the matching encodings.
+type asset struct {
+	name   string // path in Assets, e.g. "css/atom-components.css"
+	hashed string // fingerprinted path, e.g. "css/atom-components.1a2b3c4d5e.css"
+	etag   string
+	sri    string // integrity attribute value for body
+	body   []byte
+	gzip   []byte
+	br     []byte
+}
+
+var (
+	assets   = map[string]*asset{} // keyed by name
+	byHashed = map[string]*asset{} // keyed by hashed
+)
+
+func init() {
+	err := fs.WalkDir(Assets, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || isVariant(p) {
+			return err
+		}
+		body, err := fs.ReadFile(Assets, p)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(body)
+		hash := hex.EncodeToString(sum[:])
+		a := &asset{
+			name:   p,
+			hashed: fingerprint(p, hash[:10]),
+			etag:   `"` + hash + `"`,
+			body:   body,
+		}
+		minName := strings.TrimSuffix(p, path.Ext(p)) + ".min" + path.Ext(p)
+		if minified, err := fs.ReadFile(Assets, minName); err == nil {
+			a.body = minified
+			a.gzip, _ = fs.ReadFile(Assets, minName+".gz")
+			a.br, _ = fs.ReadFile(Assets, minName+".br")
+		}
+		sri := sha512.Sum384(a.body)
+		a.sri = "sha384-" + base64.StdEncoding.EncodeToString(sri[:])
+		assets[a.name] = a
+		byHashed[a.hashed] = a
+		return nil
+	})
+	if err != nil {
+		panic("static: reading embedded assets: " + err.Error())
+	}
+}
+
+// isVariant reports whether p is a generated minified or compressed copy of
+// another asset rather than an asset in its own right.
+func isVariant(p string) bool {
+	return strings.Contains(path.Base(p), ".min.") ||
+		strings.HasSuffix(p, ".gz") || strings.HasSuffix(p, ".br")
+}
+
+// fingerprint inserts hash before the file extension.
+func fingerprint(name, hash string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+// URL returns the fingerprinted URL for an embedded asset, e.g.
+// URL(CSS) == "/static/ac/css/atom-components.1a2b3c4d5e.css". The hash changes
+// whenever the file contents change, so upgrading atom-components busts
+// browser caches automatically. Unknown names are returned unhashed.
+func URL(name string) string {
+	if a, ok := assets[name]; ok {
+		return Prefix + a.hashed
+	}
+	return Prefix + name
+}
+
+// Integrity returns the Subresource Integrity value ("sha384-...") for an
+// embedded asset, computed over the bytes Handler serves. It returns "" for
+// unknown names.
+func Integrity(name string) string {
+	if a, ok := assets[name]; ok {
+		return a.sri
+	}
+	return ""
+}
+
+// Handler serves the embedded assets. Fingerprinted paths are cached forever
+// (Cache-Control: immutable); plain paths are still served but revalidated
+// on every request. Both carry an ETag and honor If-None-Match.
+//
+// Minified assets are served when present, and the precompressed brotli or
+// gzip variant is picked from Accept-Encoding, so no compression middleware
+// is needed for these files.
+//
+// Mount it with the prefix stripped:
+//
+//	e.GET("/static/ac/*", echo.WrapHandler(http.StripPrefix("/static/ac/", static.Handler())))
+func Handler() http.Handler {
+	return http.HandlerFunc(serveAsset)
+}
+
+func serveAsset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	p := strings.TrimPrefix(r.URL.Path, "/")
+	cache := "public, max-age=31536000, immutable"
+	a, ok := byHashed[p]
+	if !ok {
+		a, ok = assets[p]
+		cache = "no-cache"
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	body, etag := a.body, a.etag
+	if a.gzip != nil || a.br != nil {
+		w.Header().Add("Vary", "Accept-Encoding")
+		ae := r.Header.Get("Accept-Encoding")
+		switch {
+		case a.br != nil && acceptsEncoding(ae, "br"):
+			body, etag = a.br, strings.TrimSuffix(etag, `"`)+`-br"`
+			w.Header().Set("Content-Encoding", "br")
+		case a.gzip != nil && acceptsEncoding(ae, "gzip"):
+			body, etag = a.gzip, strings.TrimSuffix(etag, `"`)+`-gzip"`
+			w.Header().Set("Content-Encoding", "gzip")
+		}
+	}
+	w.Header().Set("Cache-Control", cache)
+	w.Header().Set("ETag", etag)
+	http.ServeContent(w, r, a.name, time.Time{}, bytes.NewReader(body))
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header value allows
+// coding, honoring q=0 and the "*" wildcard.
+func acceptsEncoding(header, coding string) bool {
+	wildcard := false
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.TrimSpace(name)
+		allowed := true
+		for _, p := range strings.Split(params, ";") {
+			k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+			if strings.EqualFold(k, "q") {
+				if q, err := strconv.ParseFloat(v, 64); err == nil && q == 0 {
+					allowed = false
+				}
+			}
+		}
+		switch {
+		case strings.EqualFold(name, coding):
+			return allowed
+		case name == "*":
+			wildcard = allowed
+		}
+	}
+	return wildcard
+}
@@ -0,0 +1,16 @@
+package static
+
+import (
+	"context"
+
+	"github.com/a-h/templ"
+)
+
+// nonceAttrs returns a nonce attribute for the CSP nonce in ctx, or nothing
+// when none is set.
+func nonceAttrs(ctx context.Context) templ.Attributes {
+	if nonce := templ.GetNonce(ctx); nonce != "" {
+		return templ.Attributes{"nonce": nonce}
+	}
+	return nil
+}
@@ -1,37 +1,681 @@
+// Atom Components — generated by go generate from js/components/*.js.
+// DO NOT EDIT.
+
+(function () {
+  "use strict";
+
+  // ============ CORE ============
+  // Helpers shared by the per-component bundles. core.js is always loaded
+  // before them; acCore is not part of the public API.
+  var core = (window.acCore = window.acCore || {});
+
+  // Overlays are shown by the data-open attribute rather than inline styles,
+  // so they work under a strict style-src CSP and host CSS can restyle them.
+  core.show = function (el) {
+    el.setAttribute("data-open", "");
+  };
+
+  core.hide = function (el) {
+    el.removeAttribute("data-open");
+  };
+
+  // core.emit dispatches a bubbling CustomEvent from el and reports whether
+  // it went through, i.e. no listener called preventDefault.
+  core.emit = function (el, name, detail, cancelable) {
+    return el.dispatchEvent(
+      new CustomEvent(name, { bubbles: true, cancelable: !!cancelable, detail: detail || {} })
+    );
+  };
+
+  // ============ DIALOG STACK ============
+  // core.open/core.close add dialog behavior on top of show/hide: focus moves
+  // into the dialog and is trapped there, everything outside it is inert, the
+  // page stops scrolling, and focus returns to the opener on close. They
+  // accept both .ac-modal-overlay elements and native <dialog>s.
+  //
+  // Open dialogs form a stack. Only the topmost one reacts to Escape and
+  // backdrop clicks, each level gets a higher z-index (set through the CSSOM,
+  // which a strict CSP allows, so any depth works), and closing a dialog
+  // returns focus to whatever opened it, down the stack.
+  var FOCUSABLE =
+    'a[href], area[href], button:not([disabled]), input:not([disabled]):not([type="hidden"]), ' +
+    'select:not([disabled]), textarea:not([disabled]), iframe, [contenteditable="true"], ' +
+    '[tabindex]:not([tabindex="-1"])';
+
+  // Open dialogs, topmost last:
+  // { overlay, opener, onEscape, onClose, inerted, uninerted, home }
+  var dialogs = [];
+
+  function dialogIndex(overlay) {
+    for (var i = 0; i < dialogs.length; i++) {
+      if (dialogs[i].overlay === overlay) return i;
+    }
+    return -1;
+  }
+
+  core.top = function () {
+    return dialogs.length ? dialogs[dialogs.length - 1].overlay : null;
+  };
+
+  core.isOpen = function (overlay) {
+    return dialogIndex(overlay) >= 0;
+  };
+
+  core.focusable = function (root) {
+    return Array.prototype.filter.call(root.querySelectorAll(FOCUSABLE), function (el) {
+      return el.getClientRects().length > 0 && getComputedStyle(el).visibility !== "hidden";
+    });
+  };
+
+  // uninertPath clears inert from the overlay and its ancestors. A dialog
+  // opened from inside (or beside) another one may sit in a subtree the
+  // lower dialog made inert; showModal doesn't lift that either. The list
+  // is recorded on entry so close can undo it.
+  function uninertPath(overlay, entry) {
+    for (var up = overlay; up && up !== document.body; up = up.parentElement) {
+      if (up.inert) {
+        up.inert = false;
+        entry.uninerted.push(up);
+      }
+    }
+  }
+
+  // inertOutside makes every sibling of the overlay and of its ancestors
+  // inert, recording them on entry.
+  function inertOutside(overlay, entry) {
+    uninertPath(overlay, entry);
+    for (var el = overlay; el && el !== document.body; el = el.parentElement) {
+      var parent = el.parentElement;
+      if (!parent) break;
+      Array.prototype.forEach.call(parent.children, function (sib) {
+        if (sib === el || sib.inert || sib.classList.contains("ac-toast-container")) return;
+        if (sib.tagName === "SCRIPT" || sib.tagName === "STYLE") return;
+        sib.inert = true;
+        entry.inerted.push(sib);
+      });
+    }
+  }
+
+  function focusInto(overlay) {
+    var target =
+      overlay.querySelector("[autofocus]") ||
+      core.focusable(overlay)[0] ||
+      overlay.querySelector('[role="dialog"]') ||
+      overlay;
+    if (!target.hasAttribute("tabindex") && core.focusable(overlay).indexOf(target) < 0) {
+      target.setAttribute("tabindex", "-1");
+    }
+    target.focus({ preventScroll: true });
+  }
+
+  // isNative reports whether el is a <dialog> the browser can show modally.
+  // Native dialogs get top-layer stacking, inertness and initial focus from
+  // the browser; everything else falls back to the data-open overlay path.
+  function isNative(el) {
+    return el.tagName === "DIALOG" && typeof el.showModal === "function";
+  }
+  core.isNative = isNative;
+
+  // A native modal dialog sits in the top layer and the browser makes
+  // everything outside it inert, so an overlay opened above one can't be
+  // seen or used where it is. It is moved into the topmost native dialog
+  // while open, and a comment keeps its place to put it back on close.
+  function blockingDialog() {
+    for (var i = dialogs.length - 1; i >= 0; i--) {
+      if (isNative(dialogs[i].overlay)) return dialogs[i].overlay;
+    }
+    return null;
+  }
+
+  function lodge(overlay, entry) {
+    var host = blockingDialog();
+    if (!host || host.contains(overlay)) return;
+    entry.home = document.createComment("ac-dialog");
+    overlay.parentNode.insertBefore(entry.home, overlay);
+    host.appendChild(overlay);
+  }
+
+  // core.open pushes overlay onto the stack. opts.onEscape runs when Escape
+  // is pressed while it is the topmost dialog; opts.onClose runs once it has
+  // closed, however that happened.
+  core.open = function (overlay, opts) {
+    if (dialogIndex(overlay) >= 0) return;
+    opts = opts || {};
+    var opener = document.activeElement;
+    var entry = {
+      overlay: overlay,
+      opener: opener && opener !== document.body ? opener : null,
+      onEscape: opts.onEscape || null,
+      onClose: opts.onClose || null,
+      inerted: [],
+      uninerted: [],
+      home: null,
+    };
+    document.documentElement.classList.add("ac-scroll-locked");
+    if (isNative(overlay)) {
+      dialogs.push(entry);
+      uninertPath(overlay, entry);
+      // A dialog rendered with the open attribute is non-modal; reopen it
+      if (overlay.open) overlay.close();
+      overlay.showModal();
+      return;
+    }
+    lodge(overlay, entry);
+    dialogs.push(entry);
+    overlay.setAttribute("data-ac-level", String(dialogs.length));
+    // The first level keeps the stylesheet's z-index so host CSS can set it
+    if (dialogs.length > 1) overlay.style.zIndex = String(9998 + dialogs.length);
+    core.show(overlay);
+    inertOutside(overlay, entry);
+    focusInto(overlay);
+  };
+
+  core.close = function (overlay) {
+    var i = dialogIndex(overlay);
+    // Overlays lodged in a native dialog can't outlive it; close them first,
+    // topmost down
+    for (var j = dialogs.length - 1; i >= 0 && j > i; j--) {
+      if (dialogs[j].home && overlay.contains(dialogs[j].overlay)) core.close(dialogs[j].overlay);
+    }
+    var entry = i >= 0 ? dialogs.splice(i, 1)[0] : null;
+    if (isNative(overlay)) {
+      if (overlay.open) overlay.close();
+    } else {
+      core.hide(overlay);
+      overlay.removeAttribute("data-ac-level");
+      overlay.style.zIndex = "";
+      if (entry && entry.home && entry.home.parentNode) {
+        entry.home.parentNode.replaceChild(overlay, entry.home);
+      }
+    }
+    if (!entry) return;
+    entry.inerted.forEach(function (el) {
+      el.inert = false;
+    });
+    entry.uninerted.forEach(function (el) {
+      el.inert = true;
+    });
+    if (dialogs.length === 0) {
+      document.documentElement.classList.remove("ac-scroll-locked");
+    }
+    if (entry.opener && document.contains(entry.opener)) {
+      entry.opener.focus({ preventScroll: true });
+    }
+    if (entry.onClose) entry.onClose();
+  };
+
+  // Native dialogs can also close themselves (Escape, method="dialog"
+  // forms); "close" doesn't bubble, so listen in the capture phase.
+  document.addEventListener(
+    "close",
+    function (e) {
+      // Ignore a stale event from a dialog that has been reopened since
+      if (dialogIndex(e.target) >= 0 && !e.target.open) core.close(e.target);
+    },
+    true
+  );
+
+  // Escape goes to the topmost dialog only. Native dialogs get it from the
+  // browser as a "cancel" event instead.
+  document.addEventListener("keydown", function (e) {
+    if (e.key !== "Escape" || dialogs.length === 0) return;
+    var entry = dialogs[dialogs.length - 1];
+    if (isNative(entry.overlay)) return;
+    // Stop a native dialog underneath from also closing
+    e.preventDefault();
+    if (entry.onEscape) entry.onEscape(e);
+  });
+
+  // Keep Tab and Shift+Tab inside the innermost dialog
+  document.addEventListener("keydown", function (e) {
+    if (e.key !== "Tab" || dialogs.length === 0) return;
+    var overlay = dialogs[dialogs.length - 1].overlay;
+    var items = core.focusable(overlay);
+    if (items.length === 0) {
+      e.preventDefault();
+      return;
+    }
+    var first = items[0];
+    var last = items[items.length - 1];
+    if (e.shiftKey && (document.activeElement === first || !overlay.contains(document.activeElement))) {
+      e.preventDefault();
+      last.focus();
+    } else if (!e.shiftKey && (document.activeElement === last || !overlay.contains(document.activeElement))) {
+      e.preventDefault();
+      first.focus();
+    }
+  });
+})();
+
 (function () {
   "use strict";
 
+  var core = window.acCore;
+
   // ============ MODAL ============
+  // data-modal-persistent: Escape and overlay clicks never close the modal.
+  // data-modal-no-overlay-close: overlay clicks don't close it.
+  //
+  // Lifecycle events, dispatched from the modal element and bubbling:
+  //   ac:modal:open         after the modal opened
+  //   ac:modal:beforeclose  before it closes; preventDefault() keeps it open.
+  //                         detail.reason is "button", "overlay", "escape",
+  //                         "swipe", "confirm", "history" or "api"
+  //   ac:modal:closed       after it closed
+  //   ac:modal:loaded       a remote modal's body was swapped in; detail.url
+  //   ac:modal:loaderror    fetching it failed; detail.url, detail.error
+  function modalDismissible(modal) {
+    return !modal.hasAttribute("data-modal-persistent");
+  }
+
+  function modalOverlayClosable(modal) {
+    return modalDismissible(modal) && !modal.hasAttribute("data-modal-no-overlay-close");
+  }
+
+  // modalOpen puts modal on the dialog stack. Escape reaches it only while it
+  // is the topmost dialog; native dialogs get Escape as "cancel" below. A
+  // url (or data-modal-url) loads the body remotely.
+  function modalOpen(modal, url) {
+    url = url || modal.getAttribute("data-modal-url");
+    if (core.isOpen(modal)) {
+      if (url) modalLoad(modal, url);
+      return;
+    }
+    core.open(modal, {
+      onEscape: function () {
+        if (modalDismissible(modal)) modalClose(modal, "escape");
+      },
+      onClose: function () {
+        modalAbort(modal);
+        if (modal.id in linked) deepLinkClosed(modal);
+        core.emit(modal, "ac:modal:closed");
+      },
+    });
+    core.emit(modal, "ac:modal:open");
+    if (deepLinkable(modal)) deepLinkOpened(modal);
+    if (url) modalLoad(modal, url);
+  }
+
+  // modalClose closes modal unless an ac:modal:beforeclose listener objects,
+  // and reports whether it closed.
+  function modalClose(modal, reason) {
+    if (!core.isOpen(modal)) return false;
+    if (!core.emit(modal, "ac:modal:beforeclose", { reason: reason }, true)) return false;
+    core.close(modal);
+    return true;
+  }
+
+  // ============ REMOTE MODALS ============
+  // The body is replaced by the HTML fragment at the URL. Requests carry an
+  // X-AC-Fragment header; a response that arrives after the modal closed or
+  // started another load is dropped.
+  var loads = {}; // modal id -> { url, controller }
+
+  function modalLoad(modal, url) {
+    var body = modal.querySelector(".ac-modal-body");
+    if (!body) return;
+    modalAbort(modal);
+    var load = { url: url, controller: window.AbortController ? new AbortController() : null };
+    loads[modal.id] = load;
+    body.setAttribute("aria-busy", "true");
+    body.innerHTML =
+      '<div class="ac-modal-loading" role="status">' +
+      '<span class="ac-modal-spinner" aria-hidden="true"></span>Loading\u2026</div>';
+    fetch(url, {
+      credentials: "same-origin",
+      headers: { Accept: "text/html", "X-AC-Fragment": "modal" },
+      signal: load.controller ? load.controller.signal : undefined,
+    })
+      .then(function (res) {
+        if (!res.ok) throw new Error("HTTP " + res.status);
+        return res.text();
+      })
+      .then(function (html) {
+        if (loads[modal.id] !== load) return;
+        body.innerHTML = html;
+        body.removeAttribute("aria-busy");
+        var auto = body.querySelector("[autofocus]");
+        if (auto) auto.focus({ preventScroll: true });
+        core.emit(modal, "ac:modal:loaded", { url: url });
+      })
+      .catch(function (err) {
+        if (loads[modal.id] !== load) return;
+        body.removeAttribute("aria-busy");
+        body.innerHTML =
+          '<div class="ac-modal-error" role="alert">' +
+          "<p>This content couldn\u2019t be loaded.</p>" +
+          '<button type="button" class="ac-modal-retry" data-modal-retry>Try again</button></div>';
+        core.emit(modal, "ac:modal:loaderror", { url: url, error: err });
+      });
+  }
+
+  // modalAbort cancels an in-flight load, keeping its URL for a retry.
+  function modalAbort(modal) {
+    var load = loads[modal.id];
+    if (!load) return;
+    loads[modal.id] = { url: load.url, controller: null };
+    if (load.controller) load.controller.abort();
+  }
+
+  function modalBackdropClick(e) {
+    var r = e.target.getBoundingClientRect();
+    return e.clientX < r.left || e.clientX > r.right || e.clientY < r.top || e.clientY > r.bottom;
+  }
+
+  // Escape on a native dialog fires "cancel". Take over so it goes through
+  // the same checks and events as every other close.
+  document.addEventListener(
+    "cancel",
+    function (e) {
+      if (!e.target.matches("dialog.ac-modal-native")) return;
+      e.preventDefault();
+      if (modalDismissible(e.target)) modalClose(e.target, "escape");
+    },
+    true
+  );
+
   document.addEventListener("click", function (e) {
     // Close button inside modal
     if (e.target.closest("[data-modal-close]")) {
-      var modal = e.target.closest(".ac-modal-overlay");
+      var modal = e.target.closest(".ac-modal-overlay, dialog.ac-modal-native");
       if (modal) {
-        modal.style.display = "none";
+        // Let the script close method="dialog" forms so lifecycle hooks run
+        if (e.target.closest('form[method="dialog"]')) e.preventDefault();
+        modalClose(modal, "button");
       }
       return;
     }
-    // Click on overlay background closes modal
-    if (e.target.classList.contains("ac-modal-overlay")) {
-      e.target.style.display = "none";
+    // Confirm dialogs: settle an acConfirm promise, or let the form submit
+    var ok = e.target.closest("[data-confirm-ok]");
+    if (ok) {
+      confirmAccept(ok, e);
+      return;
+    }
+    // Retry a failed remote load
+    var retry = e.target.closest("[data-modal-retry]");
+    if (retry) {
+      var failed = retry.closest(".ac-modal-overlay, dialog.ac-modal-native");
+      if (failed && loads[failed.id]) modalLoad(failed, loads[failed.id].url);
+      return;
+    }
+    // Only the topmost dialog reacts to backdrop clicks
+    if (e.target !== core.top()) return;
+    // Click on a native dialog's ::backdrop targets the dialog itself
+    if (e.target.matches("dialog.ac-modal-native") && modalBackdropClick(e)) {
+      if (modalOverlayClosable(e.target)) modalClose(e.target, "overlay");
+      return;
+    }
+    // Click on overlay background closes modal (datepickers handle their own)
+    if (
+      e.target.classList.contains("ac-modal-overlay") &&
+      !e.target.classList.contains("ac-datepicker-overlay") &&
+      modalOverlayClosable(e.target)
+    ) {
+      modalClose(e.target, "overlay");
     }
   });
 
-  // Escape key closes any open modal
-  document.addEventListener("keydown", function (e) {
-    if (e.key === "Escape") {
-      var modals = document.querySelectorAll('.ac-modal-overlay[style*="flex"]');
-      modals.forEach(function (modal) {
-        modal.style.display = "none";
-      });
+  // ============ DEEP LINKS ============
+  // Modals marked data-modal-deeplink open when the URL hash is their id.
+  // Opening one pushes a #id history entry, so Back closes it; closing it
+  // any other way steps back off that entry.
+  var linked = {}; // open deep-linked modal id -> whether we pushed its entry
+
+  function deepLinkable(modal) {
+    return modal.id !== "" && modal.hasAttribute("data-modal-deeplink");
+  }
+
+  function hashModal() {
+    var id;
+    try {
+      id = decodeURIComponent(location.hash.slice(1));
+    } catch (err) {
+      return null;
+    }
+    var modal = id ? document.getElementById(id) : null;
+    return modal && deepLinkable(modal) ? modal : null;
+  }
+
+  function deepLinkOpened(modal) {
+    if (location.hash === "#" + modal.id) {
+      // Opened from a link or by Forward; the entry already exists
+      linked[modal.id] = false;
+      return;
+    }
+    history.pushState({ acModal: modal.id }, "", "#" + modal.id);
+    linked[modal.id] = true;
+  }
+
+  function deepLinkClosed(modal) {
+    var pushed = linked[modal.id];
+    delete linked[modal.id];
+    if (location.hash !== "#" + modal.id) return;
+    if (pushed) {
+      history.back();
+    } else {
+      history.replaceState(history.state, "", location.pathname + location.search);
+    }
+  }
+
+  // syncHash makes the open deep-linked modals match the URL after Back,
+  // Forward or a same-page link.
+  function syncHash() {
+    Object.keys(linked).forEach(function (id) {
+      var modal = document.getElementById(id);
+      if (!modal || location.hash === "#" + id) return;
+      // Its entry is already gone; don't step back again on close
+      delete linked[id];
+      if (!modalClose(modal, "history")) {
+        // Kept open by ac:modal:beforeclose; put the entry back
+        history.pushState({ acModal: id }, "", "#" + id);
+        linked[id] = true;
+      }
+    });
+    var modal = hashModal();
+    if (modal && !core.isOpen(modal)) modalOpen(modal);
+  }
+  window.addEventListener("popstate", syncHash);
+  window.addEventListener("hashchange", syncHash);
+
+  // ============ DRAWER ============
+  // Swipe a drawer back towards its edge to dismiss it. The panel follows
+  // the finger; letting go past a third of its size (or with a quick flick)
+  // closes it, anything less snaps it back. The transform is set through
+  // the CSSOM, which a strict style-src CSP allows.
+  var swipe = null;
+
+  // swipeDelta splits touch t's movement into the dismiss direction and the
+  // one across it.
+  function swipeDelta(s, t) {
+    var dx = t.clientX - s.x;
+    var dy = t.clientY - s.y;
+    if (s.side === "left") return { along: -dx, across: dy };
+    if (s.side === "bottom") return { along: dy, across: dx };
+    return { along: dx, across: dy };
+  }
+
+  function swipeMove(s, along) {
+    var px = Math.max(0, along);
+    if (s.side === "left") px = -px;
+    s.panel.style.transform = s.side === "bottom" ? "translateY(" + px + "px)" : "translateX(" + px + "px)";
+  }
+
+  function swipeReset(panel) {
+    panel.classList.remove("ac-drawer-dragging");
+    panel.classList.add("ac-drawer-settling");
+    panel.style.transform = "";
+    setTimeout(function () {
+      panel.classList.remove("ac-drawer-settling");
+    }, 200);
+  }
+
+  document.addEventListener(
+    "touchstart",
+    function (e) {
+      var panel = e.target.closest(".ac-drawer");
+      if (!panel || e.touches.length !== 1) return;
+      var overlay = panel.parentElement;
+      if (overlay !== core.top() || !modalDismissible(overlay)) return;
+      var side = overlay.getAttribute("data-drawer-side") || "right";
+      // Let a scrolled bottom sheet scroll back up first
+      var body = panel.querySelector(".ac-modal-body");
+      if (side === "bottom" && body && body.scrollTop > 0) return;
+      swipe = {
+        panel: panel,
+        overlay: overlay,
+        side: side,
+        x: e.touches[0].clientX,
+        y: e.touches[0].clientY,
+        t: Date.now(),
+        active: false,
+      };
+    },
+    { passive: true }
+  );
+
+  document.addEventListener(
+    "touchmove",
+    function (e) {
+      if (!swipe) return;
+      var d = swipeDelta(swipe, e.touches[0]);
+      if (!swipe.active) {
+        if (Math.abs(d.along) < 10 && Math.abs(d.across) < 10) return;
+        // A mostly perpendicular gesture is a scroll, not a swipe
+        if (Math.abs(d.across) > Math.abs(d.along) || d.along < 0) {
+          swipe = null;
+          return;
+        }
+        swipe.active = true;
+        swipe.panel.classList.add("ac-drawer-dragging");
+      }
+      swipeMove(swipe, d.along);
+    },
+    { passive: true }
+  );
+
+  function swipeEnd(e) {
+    if (!swipe) return;
+    var s = swipe;
+    swipe = null;
+    if (!s.active) return;
+    var t = e.changedTouches && e.changedTouches[0];
+    var along = t ? swipeDelta(s, t).along : 0;
+    var size = s.side === "bottom" ? s.panel.offsetHeight : s.panel.offsetWidth;
+    var velocity = along / Math.max(1, Date.now() - s.t);
+    swipeReset(s.panel);
+    if (e.type === "touchend" && (along > size / 3 || velocity > 0.5)) {
+      modalClose(s.overlay, "swipe");
+    }
+  }
+  document.addEventListener("touchend", swipeEnd);
+  document.addEventListener("touchcancel", swipeEnd);
+
+  // ============ CONFIRM ============
+  // acConfirm opens a confirmation dialog and resolves to true when the user
+  // confirms and false when it is dismissed any other way. Pass the id of a
+  // rendered modal.Confirm, or options to use a shared generic dialog:
+  //   acConfirm({ message, title, confirmLabel, cancelLabel, danger })
+  // Options given with an id override the rendered text. A dialog opened
+  // this way never submits its form.
+  var confirms = {}; // modal id -> { resolve, confirmed }
+
+  var CONFIRM_DEFAULTS = {
+    title: "Are you sure?",
+    message: "",
+    confirmLabel: "Confirm",
+    cancelLabel: "Cancel",
+    danger: false,
+  };
+
+  // confirmDialog returns the generic dialog, creating it on first use with
+  // the same markup as modal.Confirm.
+  function confirmDialog() {
+    var modal = document.getElementById("ac-confirm");
+    if (modal) return modal;
+    modal = document.createElement("div");
+    modal.id = "ac-confirm";
+    modal.className = "ac-modal-overlay";
+    modal.innerHTML =
+      '<div class="ac-modal ac-modal-sm" role="dialog" aria-modal="true" aria-labelledby="ac-confirm-title" tabindex="-1">' +
+      '<div class="ac-modal-header"><h2 id="ac-confirm-title" class="ac-modal-title"></h2>' +
+      '<button class="ac-modal-close" data-modal-close aria-label="Close">&times;</button></div>' +
+      '<div class="ac-modal-body"><p class="ac-confirm-message"></p></div>' +
+      '<div class="ac-modal-footer">' +
+      '<button type="button" class="ac-confirm-btn" data-modal-close data-confirm-cancel></button>' +
+      '<button type="button" class="ac-confirm-btn" data-confirm-ok></button></div></div>';
+    document.body.appendChild(modal);
+    return modal;
+  }
+
+  function confirmFill(modal, opts) {
+    function text(selector, value) {
+      var el = modal.querySelector(selector);
+      if (el && value !== undefined) el.textContent = value;
+    }
+    text(".ac-modal-title", opts.title);
+    text(".ac-confirm-message", opts.message);
+    text("[data-confirm-ok]", opts.confirmLabel);
+    text("[data-confirm-cancel]", opts.cancelLabel);
+    var ok = modal.querySelector("[data-confirm-ok]");
+    if (ok && opts.danger !== undefined) {
+      ok.classList.toggle("ac-confirm-btn-danger", !!opts.danger);
+      ok.classList.toggle("ac-confirm-btn-primary", !opts.danger);
+    }
+  }
+
+  function confirmAccept(ok, e) {
+    var modal = ok.closest(".ac-modal-overlay, dialog.ac-modal-native");
+    if (!modal) return;
+    var pending = confirms[modal.id];
+    if (pending) {
+      e.preventDefault();
+      pending.confirmed = true;
+      if (!modalClose(modal, "confirm")) pending.confirmed = false;
+      return;
+    }
+    if (ok.type === "submit" && ok.form) {
+      // The form submits; block a second click while the page navigates
+      setTimeout(function () {
+        ok.disabled = true;
+      }, 0);
+      return;
     }
+    modalClose(modal, "confirm");
+  }
+
+  document.addEventListener("ac:modal:closed", function (e) {
+    var pending = confirms[e.target.id];
+    if (!pending) return;
+    delete confirms[e.target.id];
+    pending.resolve(pending.confirmed);
   });
 
-  // Global helper to open a modal by ID
-  window.acOpenModal = function (id) {
+  window.acConfirm = function (opts) {
+    if (typeof opts === "string") opts = { id: opts };
+    opts = opts || {};
+    var modal;
+    if (opts.id) {
+      modal = document.getElementById(opts.id);
+      if (!modal) return Promise.reject(new Error("acConfirm: no element #" + opts.id));
+      confirmFill(modal, opts);
+    } else {
+      modal = confirmDialog();
+      confirmFill(modal, Object.assign({}, CONFIRM_DEFAULTS, opts));
+    }
+    return new Promise(function (resolve) {
+      var previous = confirms[modal.id];
+      if (previous) previous.resolve(false);
+      confirms[modal.id] = { resolve: resolve, confirmed: false };
+      modalOpen(modal);
+    });
+  };
+
+  // Global helper to open a modal by ID. opts.url loads the body from that
+  // URL, overriding data-modal-url.
+  window.acOpenModal = function (id, opts) {
     var modal = document.getElementById(id);
     if (modal) {
-      modal.style.display = "flex";
+      modalOpen(modal, opts && opts.url);
     }
   };
 
@@ -39,52 +683,472 @@
   window.acCloseModal = function (id) {
     var modal = document.getElementById(id);
     if (modal) {
-      modal.style.display = "none";
+      modalClose(modal, "api");
     }
   };
 
+  // Modals rendered open by the server (OpenOnLoad) join the stack too, and
+  // a deep-linked modal named by the URL hash opens
+  function openRendered() {
+    document
+      .querySelectorAll(".ac-modal-overlay[data-open]:not(.ac-datepicker-overlay), dialog.ac-modal-native[open]")
+      .forEach(function (modal) {
+        modalOpen(modal);
+      });
+    var linkedModal = hashModal();
+    if (linkedModal) modalOpen(linkedModal);
+  }
+  if (document.readyState === "loading") {
+    document.addEventListener("DOMContentLoaded", openRendered);
+  } else {
+    openRendered();
+  }
+})();
+
+(function () {
+  "use strict";
+
   // ============ TOAST ============
+  // acToast(message, level) or acToast(message, options), options being:
+  //   level       "success", "error", "warning" or "info" (default)
+  //   title       bold first line
+  //   duration    ms before it dismisses itself (default 5000)
+  //   persistent  never dismiss automatically
+  //   html        treat message as trusted HTML instead of text
+  //   action      { label, onClick(toast) } adds a button; the toast closes
+  //               after onClick unless it returns false
+  //   key         a toast with the same key is replaced rather than stacked
+  // It returns the toast element. Server-rendered toasts (toast.Toast) take
+  // the same settings from data-toast-* attributes.
+  //
+  // The container carries the page-wide settings (toast.Container):
+  //   data-toast-position  top-right (default), top-left, bottom-right,
+  //                        bottom-left, top-center or bottom-center
+  //   data-toast-max       toasts shown at once; the rest wait in a queue
+  //   data-toast-order     "newest-first" puts new toasts nearest the edge
+  //   data-toast-duration  default duration in ms
+  //   data-toast-shortcut  keys that move focus to the toasts, e.g.
+  //                        "Alt+Shift+N"; none unless set
+  //   data-toast-stream    toast.Broker endpoint to receive pushed toasts
+  // Timers pause while a toast is hovered or focused, and a progress bar
+  // counts down the time left.
+  //
+  // Toasts carry no live-region role of their own. The container holds two
+  // persistent aria-live regions and each toast's text is announced through
+  // one of them: politely for success and info, assertively for errors and
+  // warnings.
   var toastTimer = 5000;
+  var queue = []; // toasts waiting for room, oldest first
 
-  window.acToast = function (message, level) {
-    level = level || "info";
+  function toastContainer() {
     var container = document.querySelector(".ac-toast-container");
     if (!container) {
+      // Same markup as toast.Container
       container = document.createElement("div");
       container.className = "ac-toast-container";
+      container.setAttribute("role", "region");
+      container.setAttribute("aria-label", "Notifications");
+      container.setAttribute("tabindex", "-1");
+      container.innerHTML =
+        '<div class="ac-toast-live" aria-live="polite" data-toast-live="polite"></div>' +
+        '<div class="ac-toast-live" aria-live="assertive" data-toast-live="assertive"></div>';
       document.body.appendChild(container);
     }
-    var toast = document.createElement("div");
-    toast.className = "ac-toast ac-toast-" + level;
-    toast.setAttribute("role", "alert");
-    toast.innerHTML =
-      '<span class="ac-toast-message">' +
-      message +
-      "</span>" +
-      '<button class="ac-toast-close" data-toast-close aria-label="Dismiss">&times;</button>';
-    container.appendChild(toast);
+    toastObserve(container);
+    return container;
+  }
+
+  // toastObserve picks up toasts inserted by other code, such as an htmx
+  // swap into the container, so they are timed and announced too.
+  function toastObserve(container) {
+    if (container.acToastObserver || !window.MutationObserver) return;
+    container.acToastObserver = new MutationObserver(function (records) {
+      records.forEach(function (record) {
+        Array.prototype.forEach.call(record.addedNodes, function (node) {
+          if (node.nodeType === 1 && node.classList.contains("ac-toast")) {
+            toastSchedule(container, node);
+            toastAnnounce(container, node);
+          }
+        });
+      });
+    });
+    container.acToastObserver.observe(container, { childList: true });
+  }
 
+  // toastAnnounce copies the toast's text into the live region for its
+  // level. The short delay lets a region created just now be registered by
+  // screen readers first; the line is removed again once read.
+  function toastAnnounce(container, toast) {
+    if (toast.acToastAnnounced) return;
+    toast.acToastAnnounced = true;
+    var assertive = toast.classList.contains("ac-toast-error") || toast.classList.contains("ac-toast-warning");
+    var region = container.querySelector('[data-toast-live="' + (assertive ? "assertive" : "polite") + '"]');
+    if (!region) return;
+    var parts = [];
+    toast.querySelectorAll(".ac-toast-title, .ac-toast-message").forEach(function (el) {
+      parts.push(el.textContent);
+    });
     setTimeout(function () {
-      toast.classList.add("ac-toast-exit");
+      var line = document.createElement("div");
+      line.textContent = parts.join(": ");
+      region.appendChild(line);
       setTimeout(function () {
-        toast.remove();
-      }, 300);
-    }, toastTimer);
+        line.remove();
+      }, 7000);
+    }, 100);
+  }
+
+  function toastVisible(container) {
+    return container.querySelectorAll(".ac-toast:not(.ac-toast-exit)").length;
+  }
+
+  function toastHasRoom(container) {
+    var max = parseInt(container.getAttribute("data-toast-max"), 10);
+    return !(max > 0) || toastVisible(container) < max;
+  }
+
+  // toastInsert shows toast now if there is room, otherwise queues it.
+  function toastInsert(container, toast) {
+    if (!toastHasRoom(container)) {
+      queue.push(toast);
+      return;
+    }
+    if (container.getAttribute("data-toast-order") === "newest-first") {
+      container.insertBefore(toast, container.firstChild);
+    } else {
+      container.appendChild(toast);
+    }
+    toastSchedule(container, toast);
+    toastAnnounce(container, toast);
+  }
+
+  function toastDrain() {
+    var container = toastContainer();
+    while (queue.length && toastHasRoom(container)) {
+      toastInsert(container, queue.shift());
+    }
+  }
+
+  function toastDismiss(toast) {
+    var queued = queue.indexOf(toast);
+    if (queued >= 0) {
+      queue.splice(queued, 1);
+      return;
+    }
+    if (toast.classList.contains("ac-toast-exit")) return;
+    if (toast.acToastTimer) toast.acToastTimer.stop();
+    // Keep keyboard users in the toasts rather than dropping focus on <body>
+    if (toast.contains(document.activeElement)) toastFocusNeighbor(toast);
+    toast.classList.add("ac-toast-exit");
+    setTimeout(function () {
+      toast.remove();
+    }, 300);
+    toastDrain();
+  }
+
+  function toastFocusNeighbor(toast) {
+    var container = toast.parentElement;
+    var toasts = Array.prototype.slice.call(container.querySelectorAll(".ac-toast:not(.ac-toast-exit)"));
+    var i = toasts.indexOf(toast);
+    var next = toasts[i + 1] || toasts[i - 1];
+    var target = next ? next.querySelector("[data-toast-close]") : null;
+    (target || container).focus();
+  }
+
+  // toastSchedule starts the auto-dismiss timer unless the toast is
+  // persistent. The timer pauses while the pointer is over the toast or
+  // focus is inside it; the progress bar's CSS animation pauses with it.
+  function toastSchedule(container, toast) {
+    if (toast.acToastTimer || toast.hasAttribute("data-toast-persistent")) return;
+    var ms =
+      parseInt(toast.getAttribute("data-toast-duration"), 10) ||
+      parseInt(container.getAttribute("data-toast-duration"), 10) ||
+      toastTimer;
+
+    var bar = toastElement("div", "ac-toast-progress");
+    bar.setAttribute("aria-hidden", "true");
+    bar.style.animationDuration = ms + "ms";
+    toast.appendChild(bar);
+
+    var remaining = ms;
+    var started = 0;
+    var handle = null;
+    var hovered = false;
+    var focused = false;
+
+    function run() {
+      if (handle || hovered || focused) return;
+      started = Date.now();
+      handle = setTimeout(function () {
+        toastDismiss(toast);
+      }, remaining);
+      toast.classList.remove("ac-toast-paused");
+    }
+
+    function pause() {
+      if (!handle) return;
+      clearTimeout(handle);
+      handle = null;
+      remaining -= Date.now() - started;
+      toast.classList.add("ac-toast-paused");
+    }
+
+    toast.addEventListener("mouseenter", function () {
+      hovered = true;
+      pause();
+    });
+    toast.addEventListener("mouseleave", function () {
+      hovered = false;
+      run();
+    });
+    toast.addEventListener("focusin", function () {
+      focused = true;
+      pause();
+    });
+    toast.addEventListener("focusout", function (e) {
+      if (toast.contains(e.relatedTarget)) return;
+      focused = false;
+      run();
+    });
+
+    toast.acToastTimer = {
+      stop: function () {
+        clearTimeout(handle);
+        handle = null;
+      },
+    };
+    run();
+  }
+
+  function toastElement(tag, className, text) {
+    var el = document.createElement(tag);
+    el.className = className;
+    if (text !== undefined) el.textContent = text;
+    return el;
+  }
+
+  window.acToast = function (message, options) {
+    var opts = typeof options === "object" && options !== null ? options : { level: options };
+    var level = opts.level || "info";
+
+    var toast = toastElement("div", "ac-toast ac-toast-" + level);
+    if (opts.key) toast.setAttribute("data-toast-key", opts.key);
+    if (opts.persistent) toast.setAttribute("data-toast-persistent", "");
+    if (opts.duration) toast.setAttribute("data-toast-duration", String(opts.duration));
+
+    var msg = toastElement("span", "ac-toast-message");
+    if (opts.html) {
+      msg.innerHTML = message;
+    } else {
+      msg.textContent = message;
+    }
+    if (opts.title) {
+      var text = toastElement("div", "ac-toast-text");
+      text.appendChild(toastElement("strong", "ac-toast-title", opts.title));
+      text.appendChild(msg);
+      toast.appendChild(text);
+    } else {
+      toast.appendChild(msg);
+    }
+
+    if (opts.action && opts.action.label) {
+      var action = toastElement("button", "ac-toast-action", opts.action.label);
+      action.type = "button";
+      action.addEventListener("click", function () {
+        var onClick = opts.action.onClick;
+        if (!onClick || onClick(toast) !== false) toastDismiss(toast);
+      });
+      toast.appendChild(action);
+    }
+
+    var close = toastElement("button", "ac-toast-close");
+    close.type = "button";
+    close.setAttribute("data-toast-close", "");
+    close.setAttribute("aria-label", "Dismiss");
+    close.innerHTML = "&times;";
+    toast.appendChild(close);
+
+    var container = toastContainer();
+    if (opts.key && toastReplace(container, opts.key, toast)) return toast;
+    toastInsert(container, toast);
+    return toast;
   };
 
+  // toastReplace swaps toast in for a shown or queued one with the same key.
+  function toastReplace(container, key, toast) {
+    for (var i = 0; i < queue.length; i++) {
+      if (queue[i].getAttribute("data-toast-key") === key) {
+        queue[i] = toast;
+        return true;
+      }
+    }
+    var shown = container.querySelectorAll(".ac-toast[data-toast-key]:not(.ac-toast-exit)");
+    for (var j = 0; j < shown.length; j++) {
+      if (shown[j].getAttribute("data-toast-key") === key) {
+        if (shown[j].acToastTimer) shown[j].acToastTimer.stop();
+        shown[j].replaceWith(toast);
+        toastSchedule(container, toast);
+        toastAnnounce(container, toast);
+        return true;
+      }
+    }
+    return false;
+  }
+
+  // Toasts rendered by the server are re-added oldest first, so they get
+  // the container's ordering, limit, timers and announcements like script
+  // ones. Toasts rendered outside a container, as older layouts do, are
+  // moved into it. A page with neither gets its container from the first
+  // acToast call.
+  function scheduleRendered() {
+    var rendered = Array.prototype.slice.call(document.querySelectorAll(".ac-toast"));
+    if (!rendered.length && !document.querySelector(".ac-toast-container")) return;
+    var container = toastContainer();
+    rendered.forEach(function (toast) {
+      toast.remove();
+    });
+    rendered.forEach(function (toast) {
+      toastInsert(container, toast);
+    });
+  }
+  if (document.readyState === "loading") {
+    document.addEventListener("DOMContentLoaded", scheduleRendered);
+  } else {
+    scheduleRendered();
+  }
+
   // Toast close button
   document.addEventListener("click", function (e) {
     if (e.target.closest("[data-toast-close]")) {
       var toast = e.target.closest(".ac-toast");
-      if (toast) {
-        toast.classList.add("ac-toast-exit");
-        setTimeout(function () {
-          toast.remove();
-        }, 300);
-      }
+      if (toast) toastDismiss(toast);
     }
   });
+
+  // ============ SHORTCUT ============
+  // "Alt+T", "Ctrl+Shift+N", "F8": modifiers joined by "+", key last.
+  // Letters and digits match by physical key so Alt/Option combos work on
+  // every layout.
+  function shortcutMatches(e, combo) {
+    var parts = combo.split("+");
+    var key = parts.pop().trim();
+    var mods = { alt: false, ctrl: false, shift: false, meta: false };
+    parts.forEach(function (p) {
+      mods[p.trim().toLowerCase()] = true;
+    });
+    if (e.altKey !== mods.alt || e.ctrlKey !== mods.ctrl || e.shiftKey !== mods.shift || e.metaKey !== mods.meta) {
+      return false;
+    }
+    if (/^[a-z]$/i.test(key)) return e.code === "Key" + key.toUpperCase();
+    if (/^[0-9]$/.test(key)) return e.code === "Digit" + key;
+    return e.key.toLowerCase() === key.toLowerCase();
+  }
+
+  // The shortcut focuses the first toast's controls, or the region itself.
+  // It is only bound when the container asks for one, and never while the
+  // user is typing, where Alt/Option combos produce characters.
+  function isEditable(el) {
+    return !!el && (el.isContentEditable || /^(INPUT|TEXTAREA|SELECT)$/.test(el.tagName));
+  }
+
+  document.addEventListener("keydown", function (e) {
+    var container = document.querySelector(".ac-toast-container[data-toast-shortcut]");
+    if (!container || isEditable(e.target)) return;
+    var combo = container.getAttribute("data-toast-shortcut");
+    if (!combo || !shortcutMatches(e, combo)) return;
+    e.preventDefault();
+    var first = container.querySelector(".ac-toast:not(.ac-toast-exit) button, .ac-toast:not(.ac-toast-exit) a[href]");
+    (first || container).focus();
+  });
+
+  // ============ SERVER TRIGGERS ============
+  // Handlers add toasts to a response with toast.Trigger, which writes a
+  // JSON array of {message, level} to the X-AC-Toast header. It is read from
+  // htmx responses and from requests made with acFetch. Apps that prefer
+  // HX-Trigger can send an "acToast" event with the same objects as detail.
+  function toastShowAll(list) {
+    if (!Array.isArray(list)) list = [list];
+    list.forEach(function (t) {
+      if (!t || !t.message) return;
+      // Server messages are always text; actions need a callback
+      window.acToast(t.message, {
+        level: t.level,
+        title: t.title,
+        duration: t.duration,
+        persistent: t.persistent,
+        key: t.key,
+      });
+    });
+  }
+
+  function toastFromHeader(value) {
+    if (!value) return;
+    var list;
+    try {
+      list = JSON.parse(value);
+    } catch (err) {
+      return;
+    }
+    toastShowAll(list);
+  }
+
+  document.addEventListener("htmx:afterRequest", function (e) {
+    var xhr = e.detail && e.detail.xhr;
+    if (xhr) toastFromHeader(xhr.getResponseHeader("X-AC-Toast"));
+  });
+
+  // HX-Trigger: {"acToast": {"message": "...", "level": "success"}} or an array
+  document.addEventListener("acToast", function (e) {
+    var d = e.detail;
+    if (d && d.value !== undefined) d = d.value;
+    if (d) toastShowAll(d);
+  });
+
+  // acFetch is fetch that also shows any toasts the response carries
+  window.acFetch = function (input, init) {
+    return fetch(input, init).then(function (res) {
+      toastFromHeader(res.headers.get("X-AC-Toast"));
+      return res;
+    });
+  };
+
+  // ============ STREAM ============
+  // A container with data-toast-stream listens to a toast.Broker over
+  // Server-Sent Events. EventSource reconnects by itself and sends the last
+  // event ID, so toasts published while it was away are replayed.
+  function toastStream() {
+    var container = document.querySelector(".ac-toast-container[data-toast-stream]");
+    if (!container || !window.EventSource || container.acToastStream) return;
+    var source = new EventSource(container.getAttribute("data-toast-stream"), { withCredentials: true });
+    source.addEventListener("toast", function (e) {
+      var t;
+      try {
+        t = JSON.parse(e.data);
+      } catch (err) {
+        return;
+      }
+      toastShowAll(t);
+    });
+    container.acToastStream = source;
+  }
+  if (document.readyState === "loading") {
+    document.addEventListener("DOMContentLoaded", toastStream);
+  } else {
+    toastStream();
+  }
+})();
+
+(function () {
+  "use strict";
+
+  var core = window.acCore;
+
   // ============ DATEPICKER ============
+  // Events, dispatched from the [data-ac-datepicker] root and bubbling:
+  //   ac:datepicker:open    the picker opened
+  //   ac:datepicker:change  a date was confirmed; detail.value is YYYY-MM-DD
+  //                         and detail.display the formatted label
+  //   ac:datepicker:closed  the picker closed
   var MONTHS = [
     "January",
     "February",
@@ -234,7 +1298,7 @@
 
     if (state.step === "year") {
       title.textContent = "Select Year";
-      backBtn.style.visibility = "hidden";
+      backBtn.classList.add("ac-datepicker-back-hidden");
       body.innerHTML = dpRenderYearGrid(state);
       // Auto-scroll to selected or current year
       var yearGrid = body.querySelector(".ac-datepicker-year-grid");
@@ -246,11 +1310,11 @@
       }
     } else if (state.step === "month") {
       title.textContent = state.viewYear.toString();
-      backBtn.style.visibility = "visible";
+      backBtn.classList.remove("ac-datepicker-back-hidden");
       body.innerHTML = dpRenderMonthGrid(state);
     } else if (state.step === "day") {
       title.textContent = MONTHS[state.viewMonth] + " " + state.viewYear;
-      backBtn.style.visibility = "visible";
+      backBtn.classList.remove("ac-datepicker-back-hidden");
       body.innerHTML = dpRenderDayGrid(state);
     }
   }
@@ -259,6 +1323,7 @@
     var state = dpGetState(root);
     var overlay = root.querySelector(".ac-datepicker-overlay");
     var trigger = root.querySelector("[data-ac-datepicker-trigger]");
+    if (core.isOpen(overlay)) return;
     if (state.selYear !== null) {
       state.step = "day";
       state.viewYear = state.selYear;
@@ -267,15 +1332,22 @@
       state.step = "year";
     }
     dpRender(root);
-    overlay.style.display = "flex";
     trigger.setAttribute("aria-expanded", "true");
+    core.open(overlay, {
+      onEscape: function () {
+        dpClose(root);
+      },
+      onClose: function () {
+        trigger.setAttribute("aria-expanded", "false");
+        core.emit(root, "ac:datepicker:closed");
+      },
+    });
+    core.emit(root, "ac:datepicker:open");
   }
 
   function dpClose(root) {
     var overlay = root.querySelector(".ac-datepicker-overlay");
-    var trigger = root.querySelector("[data-ac-datepicker-trigger]");
-    overlay.style.display = "none";
-    trigger.setAttribute("aria-expanded", "false");
+    core.close(overlay);
   }
 
   function dpConfirm(root) {
@@ -288,9 +1360,11 @@
 
     var hidden = root.querySelector("[data-ac-datepicker-value]");
     var trigger = root.querySelector("[data-ac-datepicker-trigger]");
+    var changed = hidden.value !== iso;
     hidden.value = iso;
     trigger.value = display;
     dpClose(root);
+    if (changed) core.emit(root, "ac:datepicker:change", { value: iso, display: display });
   }
 
   // Event delegation for datepicker clicks
@@ -319,7 +1393,7 @@
     }
 
     // Overlay background click
-    if (e.target.classList.contains("ac-datepicker-overlay")) {
+    if (e.target.classList.contains("ac-datepicker-overlay") && e.target === core.top()) {
       root = e.target.closest("[data-ac-datepicker]");
       if (root) dpClose(root);
       return;
@@ -415,19 +1489,6 @@
     }
   });
 
-  // Escape closes open datepickers
-  document.addEventListener("keydown", function (e) {
-    if (e.key === "Escape") {
-      var openPickers = document.querySelectorAll(
-        '.ac-datepicker-overlay[style*="flex"]'
-      );
-      openPickers.forEach(function (overlay) {
-        var root = overlay.closest("[data-ac-datepicker]");
-        if (root) dpClose(root);
-      });
-    }
-  });
-
   // Global helpers
   window.acOpenDatePicker = function (id) {
     var root = document.getElementById(id);
@@ -439,3 +1500,47 @@
     if (root && root.hasAttribute("data-ac-datepicker")) dpClose(root);
   };
 })();
+
+(function () {
+  "use strict";
+
+  // ============ THEME TOGGLE ============
+  var THEME_MODES = ["system", "light", "dark"];
+  var THEME_LABELS = { system: "System", light: "Light", dark: "Dark" };
+
+  function themeApply(mode) {
+    if (mode === "light" || mode === "dark") {
+      document.documentElement.setAttribute("data-theme", mode);
+    } else {
+      document.documentElement.removeAttribute("data-theme");
+    }
+    document.querySelectorAll("[data-ac-theme-toggle]").forEach(function (btn) {
+      btn.setAttribute("data-ac-theme-mode", mode);
+      btn.setAttribute("aria-label", "Theme: " + THEME_LABELS[mode]);
+      var label = btn.querySelector(".ac-theme-toggle-label");
+      if (label) label.textContent = THEME_LABELS[mode];
+    });
+  }
+
+  function themePersist(mode, cookie) {
+    document.cookie =
+      cookie + "=" + mode + "; path=/; max-age=31536000; SameSite=Lax";
+  }
+
+  document.addEventListener("click", function (e) {
+    var btn = e.target.closest("[data-ac-theme-toggle]");
+    if (!btn) return;
+    var current = THEME_MODES.indexOf(btn.getAttribute("data-ac-theme-mode"));
+    var next = THEME_MODES[(current + 1) % THEME_MODES.length];
+    themeApply(next);
+    themePersist(next, btn.getAttribute("data-ac-theme-cookie") || "ac_theme");
+  });
+
+  // Global helper to set the mode ("system", "light" or "dark")
+  window.acSetTheme = function (mode) {
+    if (THEME_MODES.indexOf(mode) < 0) return;
+    var btn = document.querySelector("[data-ac-theme-toggle]");
+    themeApply(mode);
+    themePersist(mode, (btn && btn.getAttribute("data-ac-theme-cookie")) || "ac_theme");
+  };
+})();
@@ -0,0 +1,1209 @@
+(function () {
+"use strict";
+var core = (window.acCore = window.acCore || {});
+core.show = function (el) {
+el.setAttribute("data-open", "");
+};
+core.hide = function (el) {
+el.removeAttribute("data-open");
+};
+core.emit = function (el, name, detail, cancelable) {
+return el.dispatchEvent(
+new CustomEvent(name, { bubbles: true, cancelable: !!cancelable, detail: detail || {} })
+);
+};
+var FOCUSABLE =
+'a[href], area[href], button:not([disabled]), input:not([disabled]):not([type="hidden"]), ' +
+'select:not([disabled]), textarea:not([disabled]), iframe, [contenteditable="true"], ' +
+'[tabindex]:not([tabindex="-1"])';
+var dialogs = [];
+function dialogIndex(overlay) {
+for (var i = 0; i < dialogs.length; i++) {
+if (dialogs[i].overlay === overlay) return i;
+}
+return -1;
+}
+core.top = function () {
+return dialogs.length ? dialogs[dialogs.length - 1].overlay : null;
+};
+core.isOpen = function (overlay) {
+return dialogIndex(overlay) >= 0;
+};
+core.focusable = function (root) {
+return Array.prototype.filter.call(root.querySelectorAll(FOCUSABLE), function (el) {
+return el.getClientRects().length > 0 && getComputedStyle(el).visibility !== "hidden";
+});
+};
+function uninertPath(overlay, entry) {
+for (var up = overlay; up && up !== document.body; up = up.parentElement) {
+if (up.inert) {
+up.inert = false;
+entry.uninerted.push(up);
+}
+}
+}
+function inertOutside(overlay, entry) {
+uninertPath(overlay, entry);
+for (var el = overlay; el && el !== document.body; el = el.parentElement) {
+var parent = el.parentElement;
+if (!parent) break;
+Array.prototype.forEach.call(parent.children, function (sib) {
+if (sib === el || sib.inert || sib.classList.contains("ac-toast-container")) return;
+if (sib.tagName === "SCRIPT" || sib.tagName === "STYLE") return;
+sib.inert = true;
+entry.inerted.push(sib);
+});
+}
+}
+function focusInto(overlay) {
+var target =
+overlay.querySelector("[autofocus]") ||
+core.focusable(overlay)[0] ||
+overlay.querySelector('[role="dialog"]') ||
+overlay;
+if (!target.hasAttribute("tabindex") && core.focusable(overlay).indexOf(target) < 0) {
+target.setAttribute("tabindex", "-1");
+}
+target.focus({ preventScroll: true });
+}
+function isNative(el) {
+return el.tagName === "DIALOG" && typeof el.showModal === "function";
+}
+core.isNative = isNative;
+function blockingDialog() {
+for (var i = dialogs.length - 1; i >= 0; i--) {
+if (isNative(dialogs[i].overlay)) return dialogs[i].overlay;
+}
+return null;
+}
+function lodge(overlay, entry) {
+var host = blockingDialog();
+if (!host || host.contains(overlay)) return;
+entry.home = document.createComment("ac-dialog");
+overlay.parentNode.insertBefore(entry.home, overlay);
+host.appendChild(overlay);
+}
+core.open = function (overlay, opts) {
+if (dialogIndex(overlay) >= 0) return;
+opts = opts || {};
+var opener = document.activeElement;
+var entry = {
+overlay: overlay,
+opener: opener && opener !== document.body ? opener : null,
+onEscape: opts.onEscape || null,
+onClose: opts.onClose || null,
+inerted: [],
+uninerted: [],
+home: null,
+};
+document.documentElement.classList.add("ac-scroll-locked");
+if (isNative(overlay)) {
+dialogs.push(entry);
+uninertPath(overlay, entry);
+if (overlay.open) overlay.close();
+overlay.showModal();
+return;
+}
+lodge(overlay, entry);
+dialogs.push(entry);
+overlay.setAttribute("data-ac-level", String(dialogs.length));
+if (dialogs.length > 1) overlay.style.zIndex = String(9998 + dialogs.length);
+core.show(overlay);
+inertOutside(overlay, entry);
+focusInto(overlay);
+};
+core.close = function (overlay) {
+var i = dialogIndex(overlay);
+for (var j = dialogs.length - 1; i >= 0 && j > i; j--) {
+if (dialogs[j].home && overlay.contains(dialogs[j].overlay)) core.close(dialogs[j].overlay);
+}
+var entry = i >= 0 ? dialogs.splice(i, 1)[0] : null;
+if (isNative(overlay)) {
+if (overlay.open) overlay.close();
+} else {
+core.hide(overlay);
+overlay.removeAttribute("data-ac-level");
+overlay.style.zIndex = "";
+if (entry && entry.home && entry.home.parentNode) {
+entry.home.parentNode.replaceChild(overlay, entry.home);
+}
+}
+if (!entry) return;
+entry.inerted.forEach(function (el) {
+el.inert = false;
+});
+entry.uninerted.forEach(function (el) {
+el.inert = true;
+});
+if (dialogs.length === 0) {
+document.documentElement.classList.remove("ac-scroll-locked");
+}
+if (entry.opener && document.contains(entry.opener)) {
+entry.opener.focus({ preventScroll: true });
+}
+if (entry.onClose) entry.onClose();
+};
+document.addEventListener(
+"close",
+function (e) {
+if (dialogIndex(e.target) >= 0 && !e.target.open) core.close(e.target);
+},
+true
+);
+document.addEventListener("keydown", function (e) {
+if (e.key !== "Escape" || dialogs.length === 0) return;
+var entry = dialogs[dialogs.length - 1];
+if (isNative(entry.overlay)) return;
+e.preventDefault();
+if (entry.onEscape) entry.onEscape(e);
+});
+document.addEventListener("keydown", function (e) {
+if (e.key !== "Tab" || dialogs.length === 0) return;
+var overlay = dialogs[dialogs.length - 1].overlay;
+var items = core.focusable(overlay);
+if (items.length === 0) {
+e.preventDefault();
+return;
+}
+var first = items[0];
+var last = items[items.length - 1];
+if (e.shiftKey && (document.activeElement === first || !overlay.contains(document.activeElement))) {
+e.preventDefault();
+last.focus();
+} else if (!e.shiftKey && (document.activeElement === last || !overlay.contains(document.activeElement))) {
+e.preventDefault();
+first.focus();
+}
+});
+})();
+(function () {
+"use strict";
+var core = window.acCore;
+function modalDismissible(modal) {
+return !modal.hasAttribute("data-modal-persistent");
+}
+function modalOverlayClosable(modal) {
+return modalDismissible(modal) && !modal.hasAttribute("data-modal-no-overlay-close");
+}
+function modalOpen(modal, url) {
+url = url || modal.getAttribute("data-modal-url");
+if (core.isOpen(modal)) {
+if (url) modalLoad(modal, url);
+return;
+}
+core.open(modal, {
+onEscape: function () {
+if (modalDismissible(modal)) modalClose(modal, "escape");
+},
+onClose: function () {
+modalAbort(modal);
+if (modal.id in linked) deepLinkClosed(modal);
+core.emit(modal, "ac:modal:closed");
+},
+});
+core.emit(modal, "ac:modal:open");
+if (deepLinkable(modal)) deepLinkOpened(modal);
+if (url) modalLoad(modal, url);
+}
+function modalClose(modal, reason) {
+if (!core.isOpen(modal)) return false;
+if (!core.emit(modal, "ac:modal:beforeclose", { reason: reason }, true)) return false;
+core.close(modal);
+return true;
+}
+var loads = {}; // modal id -> { url, controller }
+function modalLoad(modal, url) {
+var body = modal.querySelector(".ac-modal-body");
+if (!body) return;
+modalAbort(modal);
+var load = { url: url, controller: window.AbortController ? new AbortController() : null };
+loads[modal.id] = load;
+body.setAttribute("aria-busy", "true");
+body.innerHTML =
+'<div class="ac-modal-loading" role="status">' +
+'<span class="ac-modal-spinner" aria-hidden="true"></span>Loading\u2026</div>';
+fetch(url, {
+credentials: "same-origin",
+headers: { Accept: "text/html", "X-AC-Fragment": "modal" },
+signal: load.controller ? load.controller.signal : undefined,
+})
+.then(function (res) {
+if (!res.ok) throw new Error("HTTP " + res.status);
+return res.text();
+})
+.then(function (html) {
+if (loads[modal.id] !== load) return;
+body.innerHTML = html;
+body.removeAttribute("aria-busy");
+var auto = body.querySelector("[autofocus]");
+if (auto) auto.focus({ preventScroll: true });
+core.emit(modal, "ac:modal:loaded", { url: url });
+})
+.catch(function (err) {
+if (loads[modal.id] !== load) return;
+body.removeAttribute("aria-busy");
+body.innerHTML =
+'<div class="ac-modal-error" role="alert">' +
+"<p>This content couldn\u2019t be loaded.</p>" +
+'<button type="button" class="ac-modal-retry" data-modal-retry>Try again</button></div>';
+core.emit(modal, "ac:modal:loaderror", { url: url, error: err });
+});
+}
+function modalAbort(modal) {
+var load = loads[modal.id];
+if (!load) return;
+loads[modal.id] = { url: load.url, controller: null };
+if (load.controller) load.controller.abort();
+}
+function modalBackdropClick(e) {
+var r = e.target.getBoundingClientRect();
+return e.clientX < r.left || e.clientX > r.right || e.clientY < r.top || e.clientY > r.bottom;
+}
+document.addEventListener(
+"cancel",
+function (e) {
+if (!e.target.matches("dialog.ac-modal-native")) return;
+e.preventDefault();
+if (modalDismissible(e.target)) modalClose(e.target, "escape");
+},
+true
+);
+document.addEventListener("click", function (e) {
+if (e.target.closest("[data-modal-close]")) {
+var modal = e.target.closest(".ac-modal-overlay, dialog.ac-modal-native");
+if (modal) {
+if (e.target.closest('form[method="dialog"]')) e.preventDefault();
+modalClose(modal, "button");
+}
+return;
+}
+var ok = e.target.closest("[data-confirm-ok]");
+if (ok) {
+confirmAccept(ok, e);
+return;
+}
+var retry = e.target.closest("[data-modal-retry]");
+if (retry) {
+var failed = retry.closest(".ac-modal-overlay, dialog.ac-modal-native");
+if (failed && loads[failed.id]) modalLoad(failed, loads[failed.id].url);
+return;
+}
+if (e.target !== core.top()) return;
+if (e.target.matches("dialog.ac-modal-native") && modalBackdropClick(e)) {
+if (modalOverlayClosable(e.target)) modalClose(e.target, "overlay");
+return;
+}
+if (
+e.target.classList.contains("ac-modal-overlay") &&
+!e.target.classList.contains("ac-datepicker-overlay") &&
+modalOverlayClosable(e.target)
+) {
+modalClose(e.target, "overlay");
+}
+});
+var linked = {}; // open deep-linked modal id -> whether we pushed its entry
+function deepLinkable(modal) {
+return modal.id !== "" && modal.hasAttribute("data-modal-deeplink");
+}
+function hashModal() {
+var id;
+try {
+id = decodeURIComponent(location.hash.slice(1));
+} catch (err) {
+return null;
+}
+var modal = id ? document.getElementById(id) : null;
+return modal && deepLinkable(modal) ? modal : null;
+}
+function deepLinkOpened(modal) {
+if (location.hash === "#" + modal.id) {
+linked[modal.id] = false;
+return;
+}
+history.pushState({ acModal: modal.id }, "", "#" + modal.id);
+linked[modal.id] = true;
+}
+function deepLinkClosed(modal) {
+var pushed = linked[modal.id];
+delete linked[modal.id];
+if (location.hash !== "#" + modal.id) return;
+if (pushed) {
+history.back();
+} else {
+history.replaceState(history.state, "", location.pathname + location.search);
+}
+}
+function syncHash() {
+Object.keys(linked).forEach(function (id) {
+var modal = document.getElementById(id);
+if (!modal || location.hash === "#" + id) return;
+delete linked[id];
+if (!modalClose(modal, "history")) {
+history.pushState({ acModal: id }, "", "#" + id);
+linked[id] = true;
+}
+});
+var modal = hashModal();
+if (modal && !core.isOpen(modal)) modalOpen(modal);
+}
+window.addEventListener("popstate", syncHash);
+window.addEventListener("hashchange", syncHash);
+var swipe = null;
+function swipeDelta(s, t) {
+var dx = t.clientX - s.x;
+var dy = t.clientY - s.y;
+if (s.side === "left") return { along: -dx, across: dy };
+if (s.side === "bottom") return { along: dy, across: dx };
+return { along: dx, across: dy };
+}
+function swipeMove(s, along) {
+var px = Math.max(0, along);
+if (s.side === "left") px = -px;
+s.panel.style.transform = s.side === "bottom" ? "translateY(" + px + "px)" : "translateX(" + px + "px)";
+}
+function swipeReset(panel) {
+panel.classList.remove("ac-drawer-dragging");
+panel.classList.add("ac-drawer-settling");
+panel.style.transform = "";
+setTimeout(function () {
+panel.classList.remove("ac-drawer-settling");
+}, 200);
+}
+document.addEventListener(
+"touchstart",
+function (e) {
+var panel = e.target.closest(".ac-drawer");
+if (!panel || e.touches.length !== 1) return;
+var overlay = panel.parentElement;
+if (overlay !== core.top() || !modalDismissible(overlay)) return;
+var side = overlay.getAttribute("data-drawer-side") || "right";
+var body = panel.querySelector(".ac-modal-body");
+if (side === "bottom" && body && body.scrollTop > 0) return;
+swipe = {
+panel: panel,
+overlay: overlay,
+side: side,
+x: e.touches[0].clientX,
+y: e.touches[0].clientY,
+t: Date.now(),
+active: false,
+};
+},
+{ passive: true }
+);
+document.addEventListener(
+"touchmove",
+function (e) {
+if (!swipe) return;
+var d = swipeDelta(swipe, e.touches[0]);
+if (!swipe.active) {
+if (Math.abs(d.along) < 10 && Math.abs(d.across) < 10) return;
+if (Math.abs(d.across) > Math.abs(d.along) || d.along < 0) {
+swipe = null;
+return;
+}
+swipe.active = true;
+swipe.panel.classList.add("ac-drawer-dragging");
+}
+swipeMove(swipe, d.along);
+},
+{ passive: true }
+);
+function swipeEnd(e) {
+if (!swipe) return;
+var s = swipe;
+swipe = null;
+if (!s.active) return;
+var t = e.changedTouches && e.changedTouches[0];
+var along = t ? swipeDelta(s, t).along : 0;
+var size = s.side === "bottom" ? s.panel.offsetHeight : s.panel.offsetWidth;
+var velocity = along / Math.max(1, Date.now() - s.t);
+swipeReset(s.panel);
+if (e.type === "touchend" && (along > size / 3 || velocity > 0.5)) {
+modalClose(s.overlay, "swipe");
+}
+}
+document.addEventListener("touchend", swipeEnd);
+document.addEventListener("touchcancel", swipeEnd);
+var confirms = {}; // modal id -> { resolve, confirmed }
+var CONFIRM_DEFAULTS = {
+title: "Are you sure?",
+message: "",
+confirmLabel: "Confirm",
+cancelLabel: "Cancel",
+danger: false,
+};
+function confirmDialog() {
+var modal = document.getElementById("ac-confirm");
+if (modal) return modal;
+modal = document.createElement("div");
+modal.id = "ac-confirm";
+modal.className = "ac-modal-overlay";
+modal.innerHTML =
+'<div class="ac-modal ac-modal-sm" role="dialog" aria-modal="true" aria-labelledby="ac-confirm-title" tabindex="-1">' +
+'<div class="ac-modal-header"><h2 id="ac-confirm-title" class="ac-modal-title"></h2>' +
+'<button class="ac-modal-close" data-modal-close aria-label="Close">&times;</button></div>' +
+'<div class="ac-modal-body"><p class="ac-confirm-message"></p></div>' +
+'<div class="ac-modal-footer">' +
+'<button type="button" class="ac-confirm-btn" data-modal-close data-confirm-cancel></button>' +
+'<button type="button" class="ac-confirm-btn" data-confirm-ok></button></div></div>';
+document.body.appendChild(modal);
+return modal;
+}
+function confirmFill(modal, opts) {
+function text(selector, value) {
+var el = modal.querySelector(selector);
+if (el && value !== undefined) el.textContent = value;
+}
+text(".ac-modal-title", opts.title);
+text(".ac-confirm-message", opts.message);
+text("[data-confirm-ok]", opts.confirmLabel);
+text("[data-confirm-cancel]", opts.cancelLabel);
+var ok = modal.querySelector("[data-confirm-ok]");
+if (ok && opts.danger !== undefined) {
+ok.classList.toggle("ac-confirm-btn-danger", !!opts.danger);
+ok.classList.toggle("ac-confirm-btn-primary", !opts.danger);
+}
+}
+function confirmAccept(ok, e) {
+var modal = ok.closest(".ac-modal-overlay, dialog.ac-modal-native");
+if (!modal) return;
+var pending = confirms[modal.id];
+if (pending) {
+e.preventDefault();
+pending.confirmed = true;
+if (!modalClose(modal, "confirm")) pending.confirmed = false;
+return;
+}
+if (ok.type === "submit" && ok.form) {
+setTimeout(function () {
+ok.disabled = true;
+}, 0);
+return;
+}
+modalClose(modal, "confirm");
+}
+document.addEventListener("ac:modal:closed", function (e) {
+var pending = confirms[e.target.id];
+if (!pending) return;
+delete confirms[e.target.id];
+pending.resolve(pending.confirmed);
+});
+window.acConfirm = function (opts) {
+if (typeof opts === "string") opts = { id: opts };
+opts = opts || {};
+var modal;
+if (opts.id) {
+modal = document.getElementById(opts.id);
+if (!modal) return Promise.reject(new Error("acConfirm: no element #" + opts.id));
+confirmFill(modal, opts);
+} else {
+modal = confirmDialog();
+confirmFill(modal, Object.assign({}, CONFIRM_DEFAULTS, opts));
+}
+return new Promise(function (resolve) {
+var previous = confirms[modal.id];
+if (previous) previous.resolve(false);
+confirms[modal.id] = { resolve: resolve, confirmed: false };
+modalOpen(modal);
+});
+};
+window.acOpenModal = function (id, opts) {
+var modal = document.getElementById(id);
+if (modal) {
+modalOpen(modal, opts && opts.url);
+}
+};
+window.acCloseModal = function (id) {
+var modal = document.getElementById(id);
+if (modal) {
+modalClose(modal, "api");
+}
+};
+function openRendered() {
+document
+.querySelectorAll(".ac-modal-overlay[data-open]:not(.ac-datepicker-overlay), dialog.ac-modal-native[open]")
+.forEach(function (modal) {
+modalOpen(modal);
+});
+var linkedModal = hashModal();
+if (linkedModal) modalOpen(linkedModal);
+}
+if (document.readyState === "loading") {
+document.addEventListener("DOMContentLoaded", openRendered);
+} else {
+openRendered();
+}
+})();
+(function () {
+"use strict";
+var toastTimer = 5000;
+var queue = []; // toasts waiting for room, oldest first
+function toastContainer() {
+var container = document.querySelector(".ac-toast-container");
+if (!container) {
+container = document.createElement("div");
+container.className = "ac-toast-container";
+container.setAttribute("role", "region");
+container.setAttribute("aria-label", "Notifications");
+container.setAttribute("tabindex", "-1");
+container.innerHTML =
+'<div class="ac-toast-live" aria-live="polite" data-toast-live="polite"></div>' +
+'<div class="ac-toast-live" aria-live="assertive" data-toast-live="assertive"></div>';
+document.body.appendChild(container);
+}
+toastObserve(container);
+return container;
+}
+function toastObserve(container) {
+if (container.acToastObserver || !window.MutationObserver) return;
+container.acToastObserver = new MutationObserver(function (records) {
+records.forEach(function (record) {
+Array.prototype.forEach.call(record.addedNodes, function (node) {
+if (node.nodeType === 1 && node.classList.contains("ac-toast")) {
+toastSchedule(container, node);
+toastAnnounce(container, node);
+}
+});
+});
+});
+container.acToastObserver.observe(container, { childList: true });
+}
+function toastAnnounce(container, toast) {
+if (toast.acToastAnnounced) return;
+toast.acToastAnnounced = true;
+var assertive = toast.classList.contains("ac-toast-error") || toast.classList.contains("ac-toast-warning");
+var region = container.querySelector('[data-toast-live="' + (assertive ? "assertive" : "polite") + '"]');
+if (!region) return;
+var parts = [];
+toast.querySelectorAll(".ac-toast-title, .ac-toast-message").forEach(function (el) {
+parts.push(el.textContent);
+});
+setTimeout(function () {
+var line = document.createElement("div");
+line.textContent = parts.join(": ");
+region.appendChild(line);
+setTimeout(function () {
+line.remove();
+}, 7000);
+}, 100);
+}
+function toastVisible(container) {
+return container.querySelectorAll(".ac-toast:not(.ac-toast-exit)").length;
+}
+function toastHasRoom(container) {
+var max = parseInt(container.getAttribute("data-toast-max"), 10);
+return !(max > 0) || toastVisible(container) < max;
+}
+function toastInsert(container, toast) {
+if (!toastHasRoom(container)) {
+queue.push(toast);
+return;
+}
+if (container.getAttribute("data-toast-order") === "newest-first") {
+container.insertBefore(toast, container.firstChild);
+} else {
+container.appendChild(toast);
+}
+toastSchedule(container, toast);
+toastAnnounce(container, toast);
+}
+function toastDrain() {
+var container = toastContainer();
+while (queue.length && toastHasRoom(container)) {
+toastInsert(container, queue.shift());
+}
+}
+function toastDismiss(toast) {
+var queued = queue.indexOf(toast);
+if (queued >= 0) {
+queue.splice(queued, 1);
+return;
+}
+if (toast.classList.contains("ac-toast-exit")) return;
+if (toast.acToastTimer) toast.acToastTimer.stop();
+if (toast.contains(document.activeElement)) toastFocusNeighbor(toast);
+toast.classList.add("ac-toast-exit");
+setTimeout(function () {
+toast.remove();
+}, 300);
+toastDrain();
+}
+function toastFocusNeighbor(toast) {
+var container = toast.parentElement;
+var toasts = Array.prototype.slice.call(container.querySelectorAll(".ac-toast:not(.ac-toast-exit)"));
+var i = toasts.indexOf(toast);
+var next = toasts[i + 1] || toasts[i - 1];
+var target = next ? next.querySelector("[data-toast-close]") : null;
+(target || container).focus();
+}
+function toastSchedule(container, toast) {
+if (toast.acToastTimer || toast.hasAttribute("data-toast-persistent")) return;
+var ms =
+parseInt(toast.getAttribute("data-toast-duration"), 10) ||
+parseInt(container.getAttribute("data-toast-duration"), 10) ||
+toastTimer;
+var bar = toastElement("div", "ac-toast-progress");
+bar.setAttribute("aria-hidden", "true");
+bar.style.animationDuration = ms + "ms";
+toast.appendChild(bar);
+var remaining = ms;
+var started = 0;
+var handle = null;
+var hovered = false;
+var focused = false;
+function run() {
+if (handle || hovered || focused) return;
+started = Date.now();
+handle = setTimeout(function () {
+toastDismiss(toast);
+}, remaining);
+toast.classList.remove("ac-toast-paused");
+}
+function pause() {
+if (!handle) return;
+clearTimeout(handle);
+handle = null;
+remaining -= Date.now() - started;
+toast.classList.add("ac-toast-paused");
+}
+toast.addEventListener("mouseenter", function () {
+hovered = true;
+pause();
+});
+toast.addEventListener("mouseleave", function () {
+hovered = false;
+run();
+});
+toast.addEventListener("focusin", function () {
+focused = true;
+pause();
+});
+toast.addEventListener("focusout", function (e) {
+if (toast.contains(e.relatedTarget)) return;
+focused = false;
+run();
+});
+toast.acToastTimer = {
+stop: function () {
+clearTimeout(handle);
+handle = null;
+},
+};
+run();
+}
+function toastElement(tag, className, text) {
+var el = document.createElement(tag);
+el.className = className;
+if (text !== undefined) el.textContent = text;
+return el;
+}
+window.acToast = function (message, options) {
+var opts = typeof options === "object" && options !== null ? options : { level: options };
+var level = opts.level || "info";
+var toast = toastElement("div", "ac-toast ac-toast-" + level);
+if (opts.key) toast.setAttribute("data-toast-key", opts.key);
+if (opts.persistent) toast.setAttribute("data-toast-persistent", "");
+if (opts.duration) toast.setAttribute("data-toast-duration", String(opts.duration));
+var msg = toastElement("span", "ac-toast-message");
+if (opts.html) {
+msg.innerHTML = message;
+} else {
+msg.textContent = message;
+}
+if (opts.title) {
+var text = toastElement("div", "ac-toast-text");
+text.appendChild(toastElement("strong", "ac-toast-title", opts.title));
+text.appendChild(msg);
+toast.appendChild(text);
+} else {
+toast.appendChild(msg);
+}
+if (opts.action && opts.action.label) {
+var action = toastElement("button", "ac-toast-action", opts.action.label);
+action.type = "button";
+action.addEventListener("click", function () {
+var onClick = opts.action.onClick;
+if (!onClick || onClick(toast) !== false) toastDismiss(toast);
+});
+toast.appendChild(action);
+}
+var close = toastElement("button", "ac-toast-close");
+close.type = "button";
+close.setAttribute("data-toast-close", "");
+close.setAttribute("aria-label", "Dismiss");
+close.innerHTML = "&times;";
+toast.appendChild(close);
+var container = toastContainer();
+if (opts.key && toastReplace(container, opts.key, toast)) return toast;
+toastInsert(container, toast);
+return toast;
+};
+function toastReplace(container, key, toast) {
+for (var i = 0; i < queue.length; i++) {
+if (queue[i].getAttribute("data-toast-key") === key) {
+queue[i] = toast;
+return true;
+}
+}
+var shown = container.querySelectorAll(".ac-toast[data-toast-key]:not(.ac-toast-exit)");
+for (var j = 0; j < shown.length; j++) {
+if (shown[j].getAttribute("data-toast-key") === key) {
+if (shown[j].acToastTimer) shown[j].acToastTimer.stop();
+shown[j].replaceWith(toast);
+toastSchedule(container, toast);
+toastAnnounce(container, toast);
+return true;
+}
+}
+return false;
+}
+function scheduleRendered() {
+var rendered = Array.prototype.slice.call(document.querySelectorAll(".ac-toast"));
+if (!rendered.length && !document.querySelector(".ac-toast-container")) return;
+var container = toastContainer();
+rendered.forEach(function (toast) {
+toast.remove();
+});
+rendered.forEach(function (toast) {
+toastInsert(container, toast);
+});
+}
+if (document.readyState === "loading") {
+document.addEventListener("DOMContentLoaded", scheduleRendered);
+} else {
+scheduleRendered();
+}
+document.addEventListener("click", function (e) {
+if (e.target.closest("[data-toast-close]")) {
+var toast = e.target.closest(".ac-toast");
+if (toast) toastDismiss(toast);
+}
+});
+function shortcutMatches(e, combo) {
+var parts = combo.split("+");
+var key = parts.pop().trim();
+var mods = { alt: false, ctrl: false, shift: false, meta: false };
+parts.forEach(function (p) {
+mods[p.trim().toLowerCase()] = true;
+});
+if (e.altKey !== mods.alt || e.ctrlKey !== mods.ctrl || e.shiftKey !== mods.shift || e.metaKey !== mods.meta) {
+return false;
+}
+if (/^[a-z]$/i.test(key)) return e.code === "Key" + key.toUpperCase();
+if (/^[0-9]$/.test(key)) return e.code === "Digit" + key;
+return e.key.toLowerCase() === key.toLowerCase();
+}
+function isEditable(el) {
+return !!el && (el.isContentEditable || /^(INPUT|TEXTAREA|SELECT)$/.test(el.tagName));
+}
+document.addEventListener("keydown", function (e) {
+var container = document.querySelector(".ac-toast-container[data-toast-shortcut]");
+if (!container || isEditable(e.target)) return;
+var combo = container.getAttribute("data-toast-shortcut");
+if (!combo || !shortcutMatches(e, combo)) return;
+e.preventDefault();
+var first = container.querySelector(".ac-toast:not(.ac-toast-exit) button, .ac-toast:not(.ac-toast-exit) a[href]");
+(first || container).focus();
+});
+function toastShowAll(list) {
+if (!Array.isArray(list)) list = [list];
+list.forEach(function (t) {
+if (!t || !t.message) return;
+window.acToast(t.message, {
+level: t.level,
+title: t.title,
+duration: t.duration,
+persistent: t.persistent,
+key: t.key,
+});
+});
+}
+function toastFromHeader(value) {
+if (!value) return;
+var list;
+try {
+list = JSON.parse(value);
+} catch (err) {
+return;
+}
+toastShowAll(list);
+}
+document.addEventListener("htmx:afterRequest", function (e) {
+var xhr = e.detail && e.detail.xhr;
+if (xhr) toastFromHeader(xhr.getResponseHeader("X-AC-Toast"));
+});
+document.addEventListener("acToast", function (e) {
+var d = e.detail;
+if (d && d.value !== undefined) d = d.value;
+if (d) toastShowAll(d);
+});
+window.acFetch = function (input, init) {
+return fetch(input, init).then(function (res) {
+toastFromHeader(res.headers.get("X-AC-Toast"));
+return res;
+});
+};
+function toastStream() {
+var container = document.querySelector(".ac-toast-container[data-toast-stream]");
+if (!container || !window.EventSource || container.acToastStream) return;
+var source = new EventSource(container.getAttribute("data-toast-stream"), { withCredentials: true });
+source.addEventListener("toast", function (e) {
+var t;
+try {
+t = JSON.parse(e.data);
+} catch (err) {
+return;
+}
+toastShowAll(t);
+});
+container.acToastStream = source;
+}
+if (document.readyState === "loading") {
+document.addEventListener("DOMContentLoaded", toastStream);
+} else {
+toastStream();
+}
+})();
+(function () {
+"use strict";
+var core = window.acCore;
+var MONTHS = [
+"January",
+"February",
+"March",
+"April",
+"May",
+"June",
+"July",
+"August",
+"September",
+"October",
+"November",
+"December",
+];
+var MONTHS_SHORT = [
+"Jan",
+"Feb",
+"Mar",
+"Apr",
+"May",
+"Jun",
+"Jul",
+"Aug",
+"Sep",
+"Oct",
+"Nov",
+"Dec",
+];
+var WEEKDAYS = ["Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"];
+var datepickers = {};
+function dpGetState(root) {
+var id = root.id;
+if (!datepickers[id]) {
+var now = new Date();
+var minYear = parseInt(root.dataset.acDatepickerMinYear, 10) || now.getFullYear() - 100;
+var maxYear = parseInt(root.dataset.acDatepickerMaxYear, 10) || now.getFullYear() + 20;
+var hiddenInput = root.querySelector("[data-ac-datepicker-value]");
+var existing = hiddenInput ? hiddenInput.value : "";
+var selYear = null;
+var selMonth = null;
+var selDay = null;
+if (existing) {
+var parts = existing.split("-");
+selYear = parseInt(parts[0], 10);
+selMonth = parseInt(parts[1], 10) - 1;
+selDay = parseInt(parts[2], 10);
+}
+datepickers[id] = {
+step: "year",
+minYear: minYear,
+maxYear: maxYear,
+viewYear: selYear || now.getFullYear(),
+viewMonth: selMonth !== null ? selMonth : now.getMonth(),
+selYear: selYear,
+selMonth: selMonth,
+selDay: selDay,
+};
+}
+return datepickers[id];
+}
+function dpRenderYearGrid(state) {
+var html = '<div class="ac-datepicker-year-grid">';
+var now = new Date();
+for (var y = state.minYear; y <= state.maxYear; y++) {
+var cls = "ac-datepicker-cell";
+if (y === state.selYear) cls += " ac-datepicker-cell-selected";
+if (y === now.getFullYear()) cls += " ac-datepicker-cell-today";
+html += '<div class="' + cls + '" data-ac-datepicker-year="' + y + '">' + y + "</div>";
+}
+html += "</div>";
+return html;
+}
+function dpRenderMonthGrid(state) {
+var html = '<div class="ac-datepicker-month-grid">';
+var now = new Date();
+for (var m = 0; m < 12; m++) {
+var cls = "ac-datepicker-cell";
+if (state.selYear === state.viewYear && m === state.selMonth)
+cls += " ac-datepicker-cell-selected";
+if (state.viewYear === now.getFullYear() && m === now.getMonth())
+cls += " ac-datepicker-cell-today";
+html += '<div class="' + cls + '" data-ac-datepicker-month="' + m + '">' + MONTHS_SHORT[m] + "</div>";
+}
+html += "</div>";
+return html;
+}
+function dpRenderDayGrid(state) {
+var html = '<div class="ac-datepicker-day-grid">';
+var now = new Date();
+var todayStr = now.getFullYear() + "-" + (now.getMonth() + 1) + "-" + now.getDate();
+for (var d = 0; d < 7; d++) {
+html += '<div class="ac-datepicker-weekday">' + WEEKDAYS[d] + "</div>";
+}
+var firstDay = new Date(state.viewYear, state.viewMonth, 1).getDay();
+var daysInMonth = new Date(state.viewYear, state.viewMonth + 1, 0).getDate();
+var prevDays = new Date(state.viewYear, state.viewMonth, 0).getDate();
+for (var p = firstDay - 1; p >= 0; p--) {
+var pd = prevDays - p;
+html +=
+'<div class="ac-datepicker-cell ac-datepicker-cell-other" data-ac-datepicker-day-other="prev-' +
+pd +
+'">' +
+pd +
+"</div>";
+}
+for (var i = 1; i <= daysInMonth; i++) {
+var cls = "ac-datepicker-cell";
+var dayStr = state.viewYear + "-" + (state.viewMonth + 1) + "-" + i;
+if (state.selYear === state.viewYear && state.selMonth === state.viewMonth && state.selDay === i)
+cls += " ac-datepicker-cell-selected";
+if (dayStr === todayStr) cls += " ac-datepicker-cell-today";
+html += '<div class="' + cls + '" data-ac-datepicker-day="' + i + '">' + i + "</div>";
+}
+var totalCells = firstDay + daysInMonth;
+var remaining = 42 - totalCells;
+for (var n = 1; n <= remaining; n++) {
+html +=
+'<div class="ac-datepicker-cell ac-datepicker-cell-other" data-ac-datepicker-day-other="next-' +
+n +
+'">' +
+n +
+"</div>";
+}
+html += "</div>";
+return html;
+}
+function dpRender(root) {
+var state = dpGetState(root);
+var body = root.querySelector("[data-ac-datepicker-body]");
+var title = root.querySelector("[data-ac-datepicker-title]");
+var backBtn = root.querySelector("[data-ac-datepicker-back]");
+if (state.step === "year") {
+title.textContent = "Select Year";
+backBtn.classList.add("ac-datepicker-back-hidden");
+body.innerHTML = dpRenderYearGrid(state);
+var yearGrid = body.querySelector(".ac-datepicker-year-grid");
+var selected =
+yearGrid.querySelector(".ac-datepicker-cell-selected") ||
+yearGrid.querySelector(".ac-datepicker-cell-today");
+if (selected) {
+selected.scrollIntoView({ block: "center", behavior: "instant" });
+}
+} else if (state.step === "month") {
+title.textContent = state.viewYear.toString();
+backBtn.classList.remove("ac-datepicker-back-hidden");
+body.innerHTML = dpRenderMonthGrid(state);
+} else if (state.step === "day") {
+title.textContent = MONTHS[state.viewMonth] + " " + state.viewYear;
+backBtn.classList.remove("ac-datepicker-back-hidden");
+body.innerHTML = dpRenderDayGrid(state);
+}
+}
+function dpOpen(root) {
+var state = dpGetState(root);
+var overlay = root.querySelector(".ac-datepicker-overlay");
+var trigger = root.querySelector("[data-ac-datepicker-trigger]");
+if (core.isOpen(overlay)) return;
+if (state.selYear !== null) {
+state.step = "day";
+state.viewYear = state.selYear;
+state.viewMonth = state.selMonth;
+} else {
+state.step = "year";
+}
+dpRender(root);
+trigger.setAttribute("aria-expanded", "true");
+core.open(overlay, {
+onEscape: function () {
+dpClose(root);
+},
+onClose: function () {
+trigger.setAttribute("aria-expanded", "false");
+core.emit(root, "ac:datepicker:closed");
+},
+});
+core.emit(root, "ac:datepicker:open");
+}
+function dpClose(root) {
+var overlay = root.querySelector(".ac-datepicker-overlay");
+core.close(overlay);
+}
+function dpConfirm(root) {
+var state = dpGetState(root);
+if (state.selYear === null || state.selMonth === null || state.selDay === null) return;
+var mm = String(state.selMonth + 1).padStart(2, "0");
+var dd = String(state.selDay).padStart(2, "0");
+var iso = state.selYear + "-" + mm + "-" + dd;
+var display = MONTHS_SHORT[state.selMonth] + " " + state.selDay + ", " + state.selYear;
+var hidden = root.querySelector("[data-ac-datepicker-value]");
+var trigger = root.querySelector("[data-ac-datepicker-trigger]");
+var changed = hidden.value !== iso;
+hidden.value = iso;
+trigger.value = display;
+dpClose(root);
+if (changed) core.emit(root, "ac:datepicker:change", { value: iso, display: display });
+}
+document.addEventListener("click", function (e) {
+var root;
+if (e.target.closest("[data-ac-datepicker-trigger]")) {
+root = e.target.closest("[data-ac-datepicker]");
+if (root) dpOpen(root);
+return;
+}
+if (e.target.closest("[data-ac-datepicker-close]")) {
+root = e.target.closest("[data-ac-datepicker]");
+if (root) dpClose(root);
+return;
+}
+if (e.target.closest("[data-ac-datepicker-cancel]")) {
+root = e.target.closest("[data-ac-datepicker]");
+if (root) dpClose(root);
+return;
+}
+if (e.target.classList.contains("ac-datepicker-overlay") && e.target === core.top()) {
+root = e.target.closest("[data-ac-datepicker]");
+if (root) dpClose(root);
+return;
+}
+if (e.target.closest("[data-ac-datepicker-confirm]")) {
+root = e.target.closest("[data-ac-datepicker]");
+if (root) dpConfirm(root);
+return;
+}
+if (e.target.closest("[data-ac-datepicker-today]")) {
+root = e.target.closest("[data-ac-datepicker]");
+if (root) {
+var state = dpGetState(root);
+var now = new Date();
+state.selYear = now.getFullYear();
+state.selMonth = now.getMonth();
+state.selDay = now.getDate();
+state.viewYear = now.getFullYear();
+state.viewMonth = now.getMonth();
+state.step = "day";
+dpRender(root);
+}
+return;
+}
+if (e.target.closest("[data-ac-datepicker-back]")) {
+root = e.target.closest("[data-ac-datepicker]");
+if (root) {
+var s = dpGetState(root);
+if (s.step === "day") s.step = "month";
+else if (s.step === "month") s.step = "year";
+dpRender(root);
+}
+return;
+}
+var yearCell = e.target.closest("[data-ac-datepicker-year]");
+if (yearCell) {
+root = yearCell.closest("[data-ac-datepicker]");
+if (root) {
+var st = dpGetState(root);
+st.viewYear = parseInt(yearCell.dataset.acDatepickerYear, 10);
+st.selYear = st.viewYear;
+st.step = "month";
+dpRender(root);
+}
+return;
+}
+var monthCell = e.target.closest("[data-ac-datepicker-month]");
+if (monthCell) {
+root = monthCell.closest("[data-ac-datepicker]");
+if (root) {
+var sm = dpGetState(root);
+sm.viewMonth = parseInt(monthCell.dataset.acDatepickerMonth, 10);
+sm.selMonth = sm.viewMonth;
+sm.step = "day";
+dpRender(root);
+}
+return;
+}
+var dayCell = e.target.closest("[data-ac-datepicker-day]");
+if (dayCell) {
+root = dayCell.closest("[data-ac-datepicker]");
+if (root) {
+var sd = dpGetState(root);
+sd.selDay = parseInt(dayCell.dataset.acDatepickerDay, 10);
+dpRender(root);
+}
+return;
+}
+});
+document.addEventListener("dblclick", function (e) {
+var dayCell = e.target.closest("[data-ac-datepicker-day]");
+if (dayCell) {
+var root = dayCell.closest("[data-ac-datepicker]");
+if (root) {
+var state = dpGetState(root);
+state.selDay = parseInt(dayCell.dataset.acDatepickerDay, 10);
+dpConfirm(root);
+}
+}
+});
+window.acOpenDatePicker = function (id) {
+var root = document.getElementById(id);
+if (root && root.hasAttribute("data-ac-datepicker")) dpOpen(root);
+};
+window.acCloseDatePicker = function (id) {
+var root = document.getElementById(id);
+if (root && root.hasAttribute("data-ac-datepicker")) dpClose(root);
+};
+})();
+(function () {
+"use strict";
+var THEME_MODES = ["system", "light", "dark"];
+var THEME_LABELS = { system: "System", light: "Light", dark: "Dark" };
+function themeApply(mode) {
+if (mode === "light" || mode === "dark") {
+document.documentElement.setAttribute("data-theme", mode);
+} else {
+document.documentElement.removeAttribute("data-theme");
+}
+document.querySelectorAll("[data-ac-theme-toggle]").forEach(function (btn) {
+btn.setAttribute("data-ac-theme-mode", mode);
+btn.setAttribute("aria-label", "Theme: " + THEME_LABELS[mode]);
+var label = btn.querySelector(".ac-theme-toggle-label");
+if (label) label.textContent = THEME_LABELS[mode];
+});
+}
+function themePersist(mode, cookie) {
+document.cookie =
+cookie + "=" + mode + "; path=/; max-age=31536000; SameSite=Lax";
+}
+document.addEventListener("click", function (e) {
+var btn = e.target.closest("[data-ac-theme-toggle]");
+if (!btn) return;
+var current = THEME_MODES.indexOf(btn.getAttribute("data-ac-theme-mode"));
+var next = THEME_MODES[(current + 1) % THEME_MODES.length];
+themeApply(next);
+themePersist(next, btn.getAttribute("data-ac-theme-cookie") || "ac_theme");
+});
+window.acSetTheme = function (mode) {
+if (THEME_MODES.indexOf(mode) < 0) return;
+var btn = document.querySelector("[data-ac-theme-toggle]");
+themeApply(mode);
+themePersist(mode, (btn && btn.getAttribute("data-ac-theme-cookie")) || "ac_theme");
+};
+})();
@@ -0,0 +1,245 @@
+(function () {
+  "use strict";
+
+  // ============ CORE ============
+  // Helpers shared by the per-component bundles. core.js is always loaded
+  // before them; acCore is not part of the public API.
+  var core = (window.acCore = window.acCore || {});
+
+  // Overlays are shown by the data-open attribute rather than inline styles,
+  // so they work under a strict style-src CSP and host CSS can restyle them.
+  core.show = function (el) {
+    el.setAttribute("data-open", "");
+  };
+
+  core.hide = function (el) {
+    el.removeAttribute("data-open");
+  };
+
+  // core.emit dispatches a bubbling CustomEvent from el and reports whether
+  // it went through, i.e. no listener called preventDefault.
+  core.emit = function (el, name, detail, cancelable) {
+    return el.dispatchEvent(
+      new CustomEvent(name, { bubbles: true, cancelable: !!cancelable, detail: detail || {} })
+    );
+  };
+
+  // ============ DIALOG STACK ============
+  // core.open/core.close add dialog behavior on top of show/hide: focus moves
+  // into the dialog and is trapped there, everything outside it is inert, the
+  // page stops scrolling, and focus returns to the opener on close. They
+  // accept both .ac-modal-overlay elements and native <dialog>s.
+  //
+  // Open dialogs form a stack. Only the topmost one reacts to Escape and
+  // backdrop clicks, each level gets a higher z-index (set through the CSSOM,
+  // which a strict CSP allows, so any depth works), and closing a dialog
+  // returns focus to whatever opened it, down the stack.
+  var FOCUSABLE =
+    'a[href], area[href], button:not([disabled]), input:not([disabled]):not([type="hidden"]), ' +
+    'select:not([disabled]), textarea:not([disabled]), iframe, [contenteditable="true"], ' +
+    '[tabindex]:not([tabindex="-1"])';
+
+  // Open dialogs, topmost last:
+  // { overlay, opener, onEscape, onClose, inerted, uninerted, home }
+  var dialogs = [];
+
+  function dialogIndex(overlay) {
+    for (var i = 0; i < dialogs.length; i++) {
+      if (dialogs[i].overlay === overlay) return i;
+    }
+    return -1;
+  }
+
+  core.top = function () {
+    return dialogs.length ? dialogs[dialogs.length - 1].overlay : null;
+  };
+
+  core.isOpen = function (overlay) {
+    return dialogIndex(overlay) >= 0;
+  };
+
+  core.focusable = function (root) {
+    return Array.prototype.filter.call(root.querySelectorAll(FOCUSABLE), function (el) {
+      return el.getClientRects().length > 0 && getComputedStyle(el).visibility !== "hidden";
+    });
+  };
+
+  // uninertPath clears inert from the overlay and its ancestors. A dialog
+  // opened from inside (or beside) another one may sit in a subtree the
+  // lower dialog made inert; showModal doesn't lift that either. The list
+  // is recorded on entry so close can undo it.
+  function uninertPath(overlay, entry) {
+    for (var up = overlay; up && up !== document.body; up = up.parentElement) {
+      if (up.inert) {
+        up.inert = false;
+        entry.uninerted.push(up);
+      }
+    }
+  }
+
+  // inertOutside makes every sibling of the overlay and of its ancestors
+  // inert, recording them on entry.
+  function inertOutside(overlay, entry) {
+    uninertPath(overlay, entry);
+    for (var el = overlay; el && el !== document.body; el = el.parentElement) {
+      var parent = el.parentElement;
+      if (!parent) break;
+      Array.prototype.forEach.call(parent.children, function (sib) {
+        if (sib === el || sib.inert || sib.classList.contains("ac-toast-container")) return;
+        if (sib.tagName === "SCRIPT" || sib.tagName === "STYLE") return;
+        sib.inert = true;
+        entry.inerted.push(sib);
+      });
+    }
+  }
+
+  function focusInto(overlay) {
+    var target =
+      overlay.querySelector("[autofocus]") ||
+      core.focusable(overlay)[0] ||
+      overlay.querySelector('[role="dialog"]') ||
+      overlay;
+    if (!target.hasAttribute("tabindex") && core.focusable(overlay).indexOf(target) < 0) {
+      target.setAttribute("tabindex", "-1");
+    }
+    target.focus({ preventScroll: true });
+  }
+
+  // isNative reports whether el is a <dialog> the browser can show modally.
+  // Native dialogs get top-layer stacking, inertness and initial focus from
+  // the browser; everything else falls back to the data-open overlay path.
+  function isNative(el) {
+    return el.tagName === "DIALOG" && typeof el.showModal === "function";
+  }
+  core.isNative = isNative;
+
+  // A native modal dialog sits in the top layer and the browser makes
+  // everything outside it inert, so an overlay opened above one can't be
+  // seen or used where it is. It is moved into the topmost native dialog
+  // while open, and a comment keeps its place to put it back on close.
+  function blockingDialog() {
+    for (var i = dialogs.length - 1; i >= 0; i--) {
+      if (isNative(dialogs[i].overlay)) return dialogs[i].overlay;
+    }
+    return null;
+  }
+
+  function lodge(overlay, entry) {
+    var host = blockingDialog();
+    if (!host || host.contains(overlay)) return;
+    entry.home = document.createComment("ac-dialog");
+    overlay.parentNode.insertBefore(entry.home, overlay);
+    host.appendChild(overlay);
+  }
+
+  // core.open pushes overlay onto the stack. opts.onEscape runs when Escape
+  // is pressed while it is the topmost dialog; opts.onClose runs once it has
+  // closed, however that happened.
+  core.open = function (overlay, opts) {
+    if (dialogIndex(overlay) >= 0) return;
+    opts = opts || {};
+    var opener = document.activeElement;
+    var entry = {
+      overlay: overlay,
+      opener: opener && opener !== document.body ? opener : null,
+      onEscape: opts.onEscape || null,
+      onClose: opts.onClose || null,
+      inerted: [],
+      uninerted: [],
+      home: null,
+    };
+    document.documentElement.classList.add("ac-scroll-locked");
+    if (isNative(overlay)) {
+      dialogs.push(entry);
+      uninertPath(overlay, entry);
+      // A dialog rendered with the open attribute is non-modal; reopen it
+      if (overlay.open) overlay.close();
+      overlay.showModal();
+      return;
+    }
+    lodge(overlay, entry);
+    dialogs.push(entry);
+    overlay.setAttribute("data-ac-level", String(dialogs.length));
+    // The first level keeps the stylesheet's z-index so host CSS can set it
+    if (dialogs.length > 1) overlay.style.zIndex = String(9998 + dialogs.length);
+    core.show(overlay);
+    inertOutside(overlay, entry);
+    focusInto(overlay);
+  };
+
+  core.close = function (overlay) {
+    var i = dialogIndex(overlay);
+    // Overlays lodged in a native dialog can't outlive it; close them first,
+    // topmost down
+    for (var j = dialogs.length - 1; i >= 0 && j > i; j--) {
+      if (dialogs[j].home && overlay.contains(dialogs[j].overlay)) core.close(dialogs[j].overlay);
+    }
+    var entry = i >= 0 ? dialogs.splice(i, 1)[0] : null;
+    if (isNative(overlay)) {
+      if (overlay.open) overlay.close();
+    } else {
+      core.hide(overlay);
+      overlay.removeAttribute("data-ac-level");
+      overlay.style.zIndex = "";
+      if (entry && entry.home && entry.home.parentNode) {
+        entry.home.parentNode.replaceChild(overlay, entry.home);
+      }
+    }
+    if (!entry) return;
+    entry.inerted.forEach(function (el) {
+      el.inert = false;
+    });
+    entry.uninerted.forEach(function (el) {
+      el.inert = true;
+    });
+    if (dialogs.length === 0) {
+      document.documentElement.classList.remove("ac-scroll-locked");
+    }
+    if (entry.opener && document.contains(entry.opener)) {
+      entry.opener.focus({ preventScroll: true });
+    }
+    if (entry.onClose) entry.onClose();
+  };
+
+  // Native dialogs can also close themselves (Escape, method="dialog"
+  // forms); "close" doesn't bubble, so listen in the capture phase.
+  document.addEventListener(
+    "close",
+    function (e) {
+      // Ignore a stale event from a dialog that has been reopened since
+      if (dialogIndex(e.target) >= 0 && !e.target.open) core.close(e.target);
+    },
+    true
+  );
+
+  // Escape goes to the topmost dialog only. Native dialogs get it from the
+  // browser as a "cancel" event instead.
+  document.addEventListener("keydown", function (e) {
+    if (e.key !== "Escape" || dialogs.length === 0) return;
+    var entry = dialogs[dialogs.length - 1];
+    if (isNative(entry.overlay)) return;
+    // Stop a native dialog underneath from also closing
+    e.preventDefault();
+    if (entry.onEscape) entry.onEscape(e);
+  });
+
+  // Keep Tab and Shift+Tab inside the innermost dialog
+  document.addEventListener("keydown", function (e) {
+    if (e.key !== "Tab" || dialogs.length === 0) return;
+    var overlay = dialogs[dialogs.length - 1].overlay;
+    var items = core.focusable(overlay);
+    if (items.length === 0) {
+      e.preventDefault();
+      return;
+    }
+    var first = items[0];
+    var last = items[items.length - 1];
+    if (e.shiftKey && (document.activeElement === first || !overlay.contains(document.activeElement))) {
+      e.preventDefault();
+      last.focus();
+    } else if (!e.shiftKey && (document.activeElement === last || !overlay.contains(document.activeElement))) {
+      e.preventDefault();
+      first.focus();
+    }
+  });
+})();
@@ -0,0 +1,178 @@
+(function () {
+"use strict";
+var core = (window.acCore = window.acCore || {});
+core.show = function (el) {
+el.setAttribute("data-open", "");
+};
+core.hide = function (el) {
+el.removeAttribute("data-open");
+};
+core.emit = function (el, name, detail, cancelable) {
+return el.dispatchEvent(
+new CustomEvent(name, { bubbles: true, cancelable: !!cancelable, detail: detail || {} })
+);
+};
+var FOCUSABLE =
+'a[href], area[href], button:not([disabled]), input:not([disabled]):not([type="hidden"]), ' +
+'select:not([disabled]), textarea:not([disabled]), iframe, [contenteditable="true"], ' +
+'[tabindex]:not([tabindex="-1"])';
+var dialogs = [];
+function dialogIndex(overlay) {
+for (var i = 0; i < dialogs.length; i++) {
+if (dialogs[i].overlay === overlay) return i;
+}
+return -1;
+}
+core.top = function () {
+return dialogs.length ? dialogs[dialogs.length - 1].overlay : null;
+};
+core.isOpen = function (overlay) {
+return dialogIndex(overlay) >= 0;
+};
+core.focusable = function (root) {
+return Array.prototype.filter.call(root.querySelectorAll(FOCUSABLE), function (el) {
+return el.getClientRects().length > 0 && getComputedStyle(el).visibility !== "hidden";
+});
+};
+function uninertPath(overlay, entry) {
+for (var up = overlay; up && up !== document.body; up = up.parentElement) {
+if (up.inert) {
+up.inert = false;
+entry.uninerted.push(up);
+}
+}
+}
+function inertOutside(overlay, entry) {
+uninertPath(overlay, entry);
+for (var el = overlay; el && el !== document.body; el = el.parentElement) {
+var parent = el.parentElement;
+if (!parent) break;
+Array.prototype.forEach.call(parent.children, function (sib) {
+if (sib === el || sib.inert || sib.classList.contains("ac-toast-container")) return;
+if (sib.tagName === "SCRIPT" || sib.tagName === "STYLE") return;
+sib.inert = true;
+entry.inerted.push(sib);
+});
+}
+}
+function focusInto(overlay) {
+var target =
+overlay.querySelector("[autofocus]") ||
+core.focusable(overlay)[0] ||
+overlay.querySelector('[role="dialog"]') ||
+overlay;
+if (!target.hasAttribute("tabindex") && core.focusable(overlay).indexOf(target) < 0) {
+target.setAttribute("tabindex", "-1");
+}
+target.focus({ preventScroll: true });
+}
+function isNative(el) {
+return el.tagName === "DIALOG" && typeof el.showModal === "function";
+}
+core.isNative = isNative;
+function blockingDialog() {
+for (var i = dialogs.length - 1; i >= 0; i--) {
+if (isNative(dialogs[i].overlay)) return dialogs[i].overlay;
+}
+return null;
+}
+function lodge(overlay, entry) {
+var host = blockingDialog();
+if (!host || host.contains(overlay)) return;
+entry.home = document.createComment("ac-dialog");
+overlay.parentNode.insertBefore(entry.home, overlay);
+host.appendChild(overlay);
+}
+core.open = function (overlay, opts) {
+if (dialogIndex(overlay) >= 0) return;
+opts = opts || {};
+var opener = document.activeElement;
+var entry = {
+overlay: overlay,
+opener: opener && opener !== document.body ? opener : null,
+onEscape: opts.onEscape || null,
+onClose: opts.onClose || null,
+inerted: [],
+uninerted: [],
+home: null,
+};
+document.documentElement.classList.add("ac-scroll-locked");
+if (isNative(overlay)) {
+dialogs.push(entry);
+uninertPath(overlay, entry);
+if (overlay.open) overlay.close();
+overlay.showModal();
+return;
+}
+lodge(overlay, entry);
+dialogs.push(entry);
+overlay.setAttribute("data-ac-level", String(dialogs.length));
+if (dialogs.length > 1) overlay.style.zIndex = String(9998 + dialogs.length);
+core.show(overlay);
+inertOutside(overlay, entry);
+focusInto(overlay);
+};
+core.close = function (overlay) {
+var i = dialogIndex(overlay);
+for (var j = dialogs.length - 1; i >= 0 && j > i; j--) {
+if (dialogs[j].home && overlay.contains(dialogs[j].overlay)) core.close(dialogs[j].overlay);
+}
+var entry = i >= 0 ? dialogs.splice(i, 1)[0] : null;
+if (isNative(overlay)) {
+if (overlay.open) overlay.close();
+} else {
+core.hide(overlay);
+overlay.removeAttribute("data-ac-level");
+overlay.style.zIndex = "";
+if (entry && entry.home && entry.home.parentNode) {
+entry.home.parentNode.replaceChild(overlay, entry.home);
+}
+}
+if (!entry) return;
+entry.inerted.forEach(function (el) {
+el.inert = false;
+});
+entry.uninerted.forEach(function (el) {
+el.inert = true;
+});
+if (dialogs.length === 0) {
+document.documentElement.classList.remove("ac-scroll-locked");
+}
+if (entry.opener && document.contains(entry.opener)) {
+entry.opener.focus({ preventScroll: true });
+}
+if (entry.onClose) entry.onClose();
+};
+document.addEventListener(
+"close",
+function (e) {
+if (dialogIndex(e.target) >= 0 && !e.target.open) core.close(e.target);
+},
+true
+);
+document.addEventListener("keydown", function (e) {
+if (e.key !== "Escape" || dialogs.length === 0) return;
+var entry = dialogs[dialogs.length - 1];
+if (isNative(entry.overlay)) return;
+e.preventDefault();
+if (entry.onEscape) entry.onEscape(e);
+});
+document.addEventListener("keydown", function (e) {
+if (e.key !== "Tab" || dialogs.length === 0) return;
+var overlay = dialogs[dialogs.length - 1].overlay;
+var items = core.focusable(overlay);
+if (items.length === 0) {
+e.preventDefault();
+return;
+}
+var first = items[0];
+var last = items[items.length - 1];
+if (e.shiftKey && (document.activeElement === first || !overlay.contains(document.activeElement))) {
+e.preventDefault();
+last.focus();
+} else if (!e.shiftKey && (document.activeElement === last || !overlay.contains(document.activeElement))) {
+e.preventDefault();
+first.focus();
+}
+});
+})();
@@ -0,0 +1,359 @@
+(function () {
+"use strict";
+var core = window.acCore;
+function modalDismissible(modal) {
+return !modal.hasAttribute("data-modal-persistent");
+}
+function modalOverlayClosable(modal) {
+return modalDismissible(modal) && !modal.hasAttribute("data-modal-no-overlay-close");
+}
+function modalOpen(modal, url) {
+url = url || modal.getAttribute("data-modal-url");
+if (core.isOpen(modal)) {
+if (url) modalLoad(modal, url);
+return;
+}
+core.open(modal, {
+onEscape: function () {
+if (modalDismissible(modal)) modalClose(modal, "escape");
+},
+onClose: function () {
+modalAbort(modal);
+if (modal.id in linked) deepLinkClosed(modal);
+core.emit(modal, "ac:modal:closed");
+},
+});
+core.emit(modal, "ac:modal:open");
+if (deepLinkable(modal)) deepLinkOpened(modal);
+if (url) modalLoad(modal, url);
+}
+function modalClose(modal, reason) {
+if (!core.isOpen(modal)) return false;
+if (!core.emit(modal, "ac:modal:beforeclose", { reason: reason }, true)) return false;
+core.close(modal);
+return true;
+}
+var loads = {}; // modal id -> { url, controller }
+function modalLoad(modal, url) {
+var body = modal.querySelector(".ac-modal-body");
+if (!body) return;
+modalAbort(modal);
+var load = { url: url, controller: window.AbortController ? new AbortController() : null };
+loads[modal.id] = load;
+body.setAttribute("aria-busy", "true");
+body.innerHTML =
+'<div class="ac-modal-loading" role="status">' +
+'<span class="ac-modal-spinner" aria-hidden="true"></span>Loading\u2026</div>';
+fetch(url, {
+credentials: "same-origin",
+headers: { Accept: "text/html", "X-AC-Fragment": "modal" },
+signal: load.controller ? load.controller.signal : undefined,
+})
+.then(function (res) {
+if (!res.ok) throw new Error("HTTP " + res.status);
+return res.text();
+})
+.then(function (html) {
+if (loads[modal.id] !== load) return;
+body.innerHTML = html;
+body.removeAttribute("aria-busy");
+var auto = body.querySelector("[autofocus]");
+if (auto) auto.focus({ preventScroll: true });
+core.emit(modal, "ac:modal:loaded", { url: url });
+})
+.catch(function (err) {
+if (loads[modal.id] !== load) return;
+body.removeAttribute("aria-busy");
+body.innerHTML =
+'<div class="ac-modal-error" role="alert">' +
+"<p>This content couldn\u2019t be loaded.</p>" +
+'<button type="button" class="ac-modal-retry" data-modal-retry>Try again</button></div>';
+core.emit(modal, "ac:modal:loaderror", { url: url, error: err });
+});
+}
+function modalAbort(modal) {
+var load = loads[modal.id];
+if (!load) return;
+loads[modal.id] = { url: load.url, controller: null };
+if (load.controller) load.controller.abort();
+}
+function modalBackdropClick(e) {
+var r = e.target.getBoundingClientRect();
+return e.clientX < r.left || e.clientX > r.right || e.clientY < r.top || e.clientY > r.bottom;
+}
+document.addEventListener(
+"cancel",
+function (e) {
+if (!e.target.matches("dialog.ac-modal-native")) return;
+e.preventDefault();
+if (modalDismissible(e.target)) modalClose(e.target, "escape");
+},
+true
+);
+document.addEventListener("click", function (e) {
+if (e.target.closest("[data-modal-close]")) {
+var modal = e.target.closest(".ac-modal-overlay, dialog.ac-modal-native");
+if (modal) {
+if (e.target.closest('form[method="dialog"]')) e.preventDefault();
+modalClose(modal, "button");
+}
+return;
+}
+var ok = e.target.closest("[data-confirm-ok]");
+if (ok) {
+confirmAccept(ok, e);
+return;
+}
+var retry = e.target.closest("[data-modal-retry]");
+if (retry) {
+var failed = retry.closest(".ac-modal-overlay, dialog.ac-modal-native");
+if (failed && loads[failed.id]) modalLoad(failed, loads[failed.id].url);
+return;
+}
+if (e.target !== core.top()) return;
+if (e.target.matches("dialog.ac-modal-native") && modalBackdropClick(e)) {
+if (modalOverlayClosable(e.target)) modalClose(e.target, "overlay");
+return;
+}
+if (
+e.target.classList.contains("ac-modal-overlay") &&
+!e.target.classList.contains("ac-datepicker-overlay") &&
+modalOverlayClosable(e.target)
+) {
+modalClose(e.target, "overlay");
+}
+});
+var linked = {}; // open deep-linked modal id -> whether we pushed its entry
+function deepLinkable(modal) {
+return modal.id !== "" && modal.hasAttribute("data-modal-deeplink");
+}
+function hashModal() {
+var id;
+try {
+id = decodeURIComponent(location.hash.slice(1));
+} catch (err) {
+return null;
+}
+var modal = id ? document.getElementById(id) : null;
+return modal && deepLinkable(modal) ? modal : null;
+}
+function deepLinkOpened(modal) {
+if (location.hash === "#" + modal.id) {
+linked[modal.id] = false;
+return;
+}
+history.pushState({ acModal: modal.id }, "", "#" + modal.id);
+linked[modal.id] = true;
+}
+function deepLinkClosed(modal) {
+var pushed = linked[modal.id];
+delete linked[modal.id];
+if (location.hash !== "#" + modal.id) return;
+if (pushed) {
+history.back();
+} else {
+history.replaceState(history.state, "", location.pathname + location.search);
+}
+}
+function syncHash() {
+Object.keys(linked).forEach(function (id) {
+var modal = document.getElementById(id);
+if (!modal || location.hash === "#" + id) return;
+delete linked[id];
+if (!modalClose(modal, "history")) {
+history.pushState({ acModal: id }, "", "#" + id);
+linked[id] = true;
+}
+});
+var modal = hashModal();
+if (modal && !core.isOpen(modal)) modalOpen(modal);
+}
+window.addEventListener("popstate", syncHash);
+window.addEventListener("hashchange", syncHash);
+var swipe = null;
+function swipeDelta(s, t) {
+var dx = t.clientX - s.x;
+var dy = t.clientY - s.y;
+if (s.side === "left") return { along: -dx, across: dy };
+if (s.side === "bottom") return { along: dy, across: dx };
+return { along: dx, across: dy };
+}
+function swipeMove(s, along) {
+var px = Math.max(0, along);
+if (s.side === "left") px = -px;
+s.panel.style.transform = s.side === "bottom" ? "translateY(" + px + "px)" : "translateX(" + px + "px)";
+}
+function swipeReset(panel) {
+panel.classList.remove("ac-drawer-dragging");
+panel.classList.add("ac-drawer-settling");
+panel.style.transform = "";
+setTimeout(function () {
+panel.classList.remove("ac-drawer-settling");
+}, 200);
+}
+document.addEventListener(
+"touchstart",
+function (e) {
+var panel = e.target.closest(".ac-drawer");
+if (!panel || e.touches.length !== 1) return;
+var overlay = panel.parentElement;
+if (overlay !== core.top() || !modalDismissible(overlay)) return;
+var side = overlay.getAttribute("data-drawer-side") || "right";
+var body = panel.querySelector(".ac-modal-body");
+if (side === "bottom" && body && body.scrollTop > 0) return;
+swipe = {
+panel: panel,
+overlay: overlay,
+side: side,
+x: e.touches[0].clientX,
+y: e.touches[0].clientY,
+t: Date.now(),
+active: false,
+};
+},
+{ passive: true }
+);
+document.addEventListener(
+"touchmove",
+function (e) {
+if (!swipe) return;
+var d = swipeDelta(swipe, e.touches[0]);
+if (!swipe.active) {
+if (Math.abs(d.along) < 10 && Math.abs(d.across) < 10) return;
+if (Math.abs(d.across) > Math.abs(d.along) || d.along < 0) {
+swipe = null;
+return;
+}
+swipe.active = true;
+swipe.panel.classList.add("ac-drawer-dragging");
+}
+swipeMove(swipe, d.along);
+},
+{ passive: true }
+);
+function swipeEnd(e) {
+if (!swipe) return;
+var s = swipe;
+swipe = null;
+if (!s.active) return;
+var t = e.changedTouches && e.changedTouches[0];
+var along = t ? swipeDelta(s, t).along : 0;
+var size = s.side === "bottom" ? s.panel.offsetHeight : s.panel.offsetWidth;
+var velocity = along / Math.max(1, Date.now() - s.t);
+swipeReset(s.panel);
+if (e.type === "touchend" && (along > size / 3 || velocity > 0.5)) {
+modalClose(s.overlay, "swipe");
+}
+}
+document.addEventListener("touchend", swipeEnd);
+document.addEventListener("touchcancel", swipeEnd);
+var confirms = {}; // modal id -> { resolve, confirmed }
+var CONFIRM_DEFAULTS = {
+title: "Are you sure?",
+message: "",
+confirmLabel: "Confirm",
+cancelLabel: "Cancel",
+danger: false,
+};
+function confirmDialog() {
+var modal = document.getElementById("ac-confirm");
+if (modal) return modal;
+modal = document.createElement("div");
+modal.id = "ac-confirm";
+modal.className = "ac-modal-overlay";
+modal.innerHTML =
+'<div class="ac-modal ac-modal-sm" role="dialog" aria-modal="true" aria-labelledby="ac-confirm-title" tabindex="-1">' +
+'<div class="ac-modal-header"><h2 id="ac-confirm-title" class="ac-modal-title"></h2>' +
+'<button class="ac-modal-close" data-modal-close aria-label="Close">&times;</button></div>' +
+'<div class="ac-modal-body"><p class="ac-confirm-message"></p></div>' +
+'<div class="ac-modal-footer">' +
+'<button type="button" class="ac-confirm-btn" data-modal-close data-confirm-cancel></button>' +
+'<button type="button" class="ac-confirm-btn" data-confirm-ok></button></div></div>';
+document.body.appendChild(modal);
+return modal;
+}
+function confirmFill(modal, opts) {
+function text(selector, value) {
+var el = modal.querySelector(selector);
+if (el && value !== undefined) el.textContent = value;
+}
+text(".ac-modal-title", opts.title);
+text(".ac-confirm-message", opts.message);
+text("[data-confirm-ok]", opts.confirmLabel);
+text("[data-confirm-cancel]", opts.cancelLabel);
+var ok = modal.querySelector("[data-confirm-ok]");
+if (ok && opts.danger !== undefined) {
+ok.classList.toggle("ac-confirm-btn-danger", !!opts.danger);
+ok.classList.toggle("ac-confirm-btn-primary", !opts.danger);
+}
+}
+function confirmAccept(ok, e) {
+var modal = ok.closest(".ac-modal-overlay, dialog.ac-modal-native");
+if (!modal) return;
+var pending = confirms[modal.id];
+if (pending) {
+e.preventDefault();
+pending.confirmed = true;
+if (!modalClose(modal, "confirm")) pending.confirmed = false;
+return;
+}
+if (ok.type === "submit" && ok.form) {
+setTimeout(function () {
+ok.disabled = true;
+}, 0);
+return;
+}
+modalClose(modal, "confirm");
+}
+document.addEventListener("ac:modal:closed", function (e) {
+var pending = confirms[e.target.id];
+if (!pending) return;
+delete confirms[e.target.id];
+pending.resolve(pending.confirmed);
+});
+window.acConfirm = function (opts) {
+if (typeof opts === "string") opts = { id: opts };
+opts = opts || {};
+var modal;
+if (opts.id) {
+modal = document.getElementById(opts.id);
+if (!modal) return Promise.reject(new Error("acConfirm: no element #" + opts.id));
+confirmFill(modal, opts);
+} else {
+modal = confirmDialog();
+confirmFill(modal, Object.assign({}, CONFIRM_DEFAULTS, opts));
+}
+return new Promise(function (resolve) {
+var previous = confirms[modal.id];
+if (previous) previous.resolve(false);
+confirms[modal.id] = { resolve: resolve, confirmed: false };
+modalOpen(modal);
+});
+};
+window.acOpenModal = function (id, opts) {
+var modal = document.getElementById(id);
+if (modal) {
+modalOpen(modal, opts && opts.url);
+}
+};
+window.acCloseModal = function (id) {
+var modal = document.getElementById(id);
+if (modal) {
+modalClose(modal, "api");
+}
+};
+function openRendered() {
+document
+.querySelectorAll(".ac-modal-overlay[data-open]:not(.ac-datepicker-overlay), dialog.ac-modal-native[open]")
+.forEach(function (modal) {
+modalOpen(modal);
+});
+var linkedModal = hashModal();
+if (linkedModal) modalOpen(linkedModal);
+}
+if (document.readyState === "loading") {
+document.addEventListener("DOMContentLoaded", openRendered);
+} else {
+openRendered();
+}
+})();
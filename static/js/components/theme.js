@@ -0,0 +1,43 @@
+(function () {
+  "use strict";
+
+  // ============ THEME TOGGLE ============
+  var THEME_MODES = ["system", "light", "dark"];
+  var THEME_LABELS = { system: "System", light: "Light", dark: "Dark" };
+
+  function themeApply(mode) {
+    if (mode === "light" || mode === "dark") {
+      document.documentElement.setAttribute("data-theme", mode);
+    } else {
+      document.documentElement.removeAttribute("data-theme");
+    }
+    document.querySelectorAll("[data-ac-theme-toggle]").forEach(function (btn) {
+      btn.setAttribute("data-ac-theme-mode", mode);
+      btn.setAttribute("aria-label", "Theme: " + THEME_LABELS[mode]);
+      var label = btn.querySelector(".ac-theme-toggle-label");
+      if (label) label.textContent = THEME_LABELS[mode];
+    });
+  }
+
+  function themePersist(mode, cookie) {
+    document.cookie =
+      cookie + "=" + mode + "; path=/; max-age=31536000; SameSite=Lax";
+  }
+
+  document.addEventListener("click", function (e) {
+    var btn = e.target.closest("[data-ac-theme-toggle]");
+    if (!btn) return;
+    var current = THEME_MODES.indexOf(btn.getAttribute("data-ac-theme-mode"));
+    var next = THEME_MODES[(current + 1) % THEME_MODES.length];
+    themeApply(next);
+    themePersist(next, btn.getAttribute("data-ac-theme-cookie") || "ac_theme");
+  });
+
+  // Global helper to set the mode ("system", "light" or "dark")
+  window.acSetTheme = function (mode) {
+    if (THEME_MODES.indexOf(mode) < 0) return;
+    var btn = document.querySelector("[data-ac-theme-toggle]");
+    themeApply(mode);
+    themePersist(mode, (btn && btn.getAttribute("data-ac-theme-cookie")) || "ac_theme");
+  };
+})();
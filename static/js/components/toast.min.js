@@ -0,0 +1,330 @@
+(function () {
+"use strict";
+var toastTimer = 5000;
+var queue = []; // toasts waiting for room, oldest first
+function toastContainer() {
+var container = document.querySelector(".ac-toast-container");
+if (!container) {
+container = document.createElement("div");
+container.className = "ac-toast-container";
+container.setAttribute("role", "region");
+container.setAttribute("aria-label", "Notifications");
+container.setAttribute("tabindex", "-1");
+container.innerHTML =
+'<div class="ac-toast-live" aria-live="polite" data-toast-live="polite"></div>' +
+'<div class="ac-toast-live" aria-live="assertive" data-toast-live="assertive"></div>';
+document.body.appendChild(container);
+}
+toastObserve(container);
+return container;
+}
+function toastObserve(container) {
+if (container.acToastObserver || !window.MutationObserver) return;
+container.acToastObserver = new MutationObserver(function (records) {
+records.forEach(function (record) {
+Array.prototype.forEach.call(record.addedNodes, function (node) {
+if (node.nodeType === 1 && node.classList.contains("ac-toast")) {
+toastSchedule(container, node);
+toastAnnounce(container, node);
+}
+});
+});
+});
+container.acToastObserver.observe(container, { childList: true });
+}
+function toastAnnounce(container, toast) {
+if (toast.acToastAnnounced) return;
+toast.acToastAnnounced = true;
+var assertive = toast.classList.contains("ac-toast-error") || toast.classList.contains("ac-toast-warning");
+var region = container.querySelector('[data-toast-live="' + (assertive ? "assertive" : "polite") + '"]');
+if (!region) return;
+var parts = [];
+toast.querySelectorAll(".ac-toast-title, .ac-toast-message").forEach(function (el) {
+parts.push(el.textContent);
+});
+setTimeout(function () {
+var line = document.createElement("div");
+line.textContent = parts.join(": ");
+region.appendChild(line);
+setTimeout(function () {
+line.remove();
+}, 7000);
+}, 100);
+}
+function toastVisible(container) {
+return container.querySelectorAll(".ac-toast:not(.ac-toast-exit)").length;
+}
+function toastHasRoom(container) {
+var max = parseInt(container.getAttribute("data-toast-max"), 10);
+return !(max > 0) || toastVisible(container) < max;
+}
+function toastInsert(container, toast) {
+if (!toastHasRoom(container)) {
+queue.push(toast);
+return;
+}
+if (container.getAttribute("data-toast-order") === "newest-first") {
+container.insertBefore(toast, container.firstChild);
+} else {
+container.appendChild(toast);
+}
+toastSchedule(container, toast);
+toastAnnounce(container, toast);
+}
+function toastDrain() {
+var container = toastContainer();
+while (queue.length && toastHasRoom(container)) {
+toastInsert(container, queue.shift());
+}
+}
+function toastDismiss(toast) {
+var queued = queue.indexOf(toast);
+if (queued >= 0) {
+queue.splice(queued, 1);
+return;
+}
+if (toast.classList.contains("ac-toast-exit")) return;
+if (toast.acToastTimer) toast.acToastTimer.stop();
+if (toast.contains(document.activeElement)) toastFocusNeighbor(toast);
+toast.classList.add("ac-toast-exit");
+setTimeout(function () {
+toast.remove();
+}, 300);
+toastDrain();
+}
+function toastFocusNeighbor(toast) {
+var container = toast.parentElement;
+var toasts = Array.prototype.slice.call(container.querySelectorAll(".ac-toast:not(.ac-toast-exit)"));
+var i = toasts.indexOf(toast);
+var next = toasts[i + 1] || toasts[i - 1];
+var target = next ? next.querySelector("[data-toast-close]") : null;
+(target || container).focus();
+}
+function toastSchedule(container, toast) {
+if (toast.acToastTimer || toast.hasAttribute("data-toast-persistent")) return;
+var ms =
+parseInt(toast.getAttribute("data-toast-duration"), 10) ||
+parseInt(container.getAttribute("data-toast-duration"), 10) ||
+toastTimer;
+var bar = toastElement("div", "ac-toast-progress");
+bar.setAttribute("aria-hidden", "true");
+bar.style.animationDuration = ms + "ms";
+toast.appendChild(bar);
+var remaining = ms;
+var started = 0;
+var handle = null;
+var hovered = false;
+var focused = false;
+function run() {
+if (handle || hovered || focused) return;
+started = Date.now();
+handle = setTimeout(function () {
+toastDismiss(toast);
+}, remaining);
+toast.classList.remove("ac-toast-paused");
+}
+function pause() {
+if (!handle) return;
+clearTimeout(handle);
+handle = null;
+remaining -= Date.now() - started;
+toast.classList.add("ac-toast-paused");
+}
+toast.addEventListener("mouseenter", function () {
+hovered = true;
+pause();
+});
+toast.addEventListener("mouseleave", function () {
+hovered = false;
+run();
+});
+toast.addEventListener("focusin", function () {
+focused = true;
+pause();
+});
+toast.addEventListener("focusout", function (e) {
+if (toast.contains(e.relatedTarget)) return;
+focused = false;
+run();
+});
+toast.acToastTimer = {
+stop: function () {
+clearTimeout(handle);
+handle = null;
+},
+};
+run();
+}
+function toastElement(tag, className, text) {
+var el = document.createElement(tag);
+el.className = className;
+if (text !== undefined) el.textContent = text;
+return el;
+}
+window.acToast = function (message, options) {
+var opts = typeof options === "object" && options !== null ? options : { level: options };
+var level = opts.level || "info";
+var toast = toastElement("div", "ac-toast ac-toast-" + level);
+if (opts.key) toast.setAttribute("data-toast-key", opts.key);
+if (opts.persistent) toast.setAttribute("data-toast-persistent", "");
+if (opts.duration) toast.setAttribute("data-toast-duration", String(opts.duration));
+var msg = toastElement("span", "ac-toast-message");
+if (opts.html) {
+msg.innerHTML = message;
+} else {
+msg.textContent = message;
+}
+if (opts.title) {
+var text = toastElement("div", "ac-toast-text");
+text.appendChild(toastElement("strong", "ac-toast-title", opts.title));
+text.appendChild(msg);
+toast.appendChild(text);
+} else {
+toast.appendChild(msg);
+}
+if (opts.action && opts.action.label) {
+var action = toastElement("button", "ac-toast-action", opts.action.label);
+action.type = "button";
+action.addEventListener("click", function () {
+var onClick = opts.action.onClick;
+if (!onClick || onClick(toast) !== false) toastDismiss(toast);
+});
+toast.appendChild(action);
+}
+var close = toastElement("button", "ac-toast-close");
+close.type = "button";
+close.setAttribute("data-toast-close", "");
+close.setAttribute("aria-label", "Dismiss");
+close.innerHTML = "&times;";
+toast.appendChild(close);
+var container = toastContainer();
+if (opts.key && toastReplace(container, opts.key, toast)) return toast;
+toastInsert(container, toast);
+return toast;
+};
+function toastReplace(container, key, toast) {
+for (var i = 0; i < queue.length; i++) {
+if (queue[i].getAttribute("data-toast-key") === key) {
+queue[i] = toast;
+return true;
+}
+}
+var shown = container.querySelectorAll(".ac-toast[data-toast-key]:not(.ac-toast-exit)");
+for (var j = 0; j < shown.length; j++) {
+if (shown[j].getAttribute("data-toast-key") === key) {
+if (shown[j].acToastTimer) shown[j].acToastTimer.stop();
+shown[j].replaceWith(toast);
+toastSchedule(container, toast);
+toastAnnounce(container, toast);
+return true;
+}
+}
+return false;
+}
+function scheduleRendered() {
+var rendered = Array.prototype.slice.call(document.querySelectorAll(".ac-toast"));
+if (!rendered.length && !document.querySelector(".ac-toast-container")) return;
+var container = toastContainer();
+rendered.forEach(function (toast) {
+toast.remove();
+});
+rendered.forEach(function (toast) {
+toastInsert(container, toast);
+});
+}
+if (document.readyState === "loading") {
+document.addEventListener("DOMContentLoaded", scheduleRendered);
+} else {
+scheduleRendered();
+}
+document.addEventListener("click", function (e) {
+if (e.target.closest("[data-toast-close]")) {
+var toast = e.target.closest(".ac-toast");
+if (toast) toastDismiss(toast);
+}
+});
+function shortcutMatches(e, combo) {
+var parts = combo.split("+");
+var key = parts.pop().trim();
+var mods = { alt: false, ctrl: false, shift: false, meta: false };
+parts.forEach(function (p) {
+mods[p.trim().toLowerCase()] = true;
+});
+if (e.altKey !== mods.alt || e.ctrlKey !== mods.ctrl || e.shiftKey !== mods.shift || e.metaKey !== mods.meta) {
+return false;
+}
+if (/^[a-z]$/i.test(key)) return e.code === "Key" + key.toUpperCase();
+if (/^[0-9]$/.test(key)) return e.code === "Digit" + key;
+return e.key.toLowerCase() === key.toLowerCase();
+}
+function isEditable(el) {
+return !!el && (el.isContentEditable || /^(INPUT|TEXTAREA|SELECT)$/.test(el.tagName));
+}
+document.addEventListener("keydown", function (e) {
+var container = document.querySelector(".ac-toast-container[data-toast-shortcut]");
+if (!container || isEditable(e.target)) return;
+var combo = container.getAttribute("data-toast-shortcut");
+if (!combo || !shortcutMatches(e, combo)) return;
+e.preventDefault();
+var first = container.querySelector(".ac-toast:not(.ac-toast-exit) button, .ac-toast:not(.ac-toast-exit) a[href]");
+(first || container).focus();
+});
+function toastShowAll(list) {
+if (!Array.isArray(list)) list = [list];
+list.forEach(function (t) {
+if (!t || !t.message) return;
+window.acToast(t.message, {
+level: t.level,
+title: t.title,
+duration: t.duration,
+persistent: t.persistent,
+key: t.key,
+});
+});
+}
+function toastFromHeader(value) {
+if (!value) return;
+var list;
+try {
+list = JSON.parse(value);
+} catch (err) {
+return;
+}
+toastShowAll(list);
+}
+document.addEventListener("htmx:afterRequest", function (e) {
+var xhr = e.detail && e.detail.xhr;
+if (xhr) toastFromHeader(xhr.getResponseHeader("X-AC-Toast"));
+});
+document.addEventListener("acToast", function (e) {
+var d = e.detail;
+if (d && d.value !== undefined) d = d.value;
+if (d) toastShowAll(d);
+});
+window.acFetch = function (input, init) {
+return fetch(input, init).then(function (res) {
+toastFromHeader(res.headers.get("X-AC-Toast"));
+return res;
+});
+};
+function toastStream() {
+var container = document.querySelector(".ac-toast-container[data-toast-stream]");
+if (!container || !window.EventSource || container.acToastStream) return;
+var source = new EventSource(container.getAttribute("data-toast-stream"), { withCredentials: true });
+source.addEventListener("toast", function (e) {
+var t;
+try {
+t = JSON.parse(e.data);
+} catch (err) {
+return;
+}
+toastShowAll(t);
+});
+container.acToastStream = source;
+}
+if (document.readyState === "loading") {
+document.addEventListener("DOMContentLoaded", toastStream);
+} else {
+toastStream();
+}
+})();
@@ -2,5 +2,7 @@ package static
 
 import "embed"
 
+//go:generate go run ../internal/assetgen
+
 //go:embed css/* js/*
 var Assets embed.FS
@@ -0,0 +1,351 @@
+package static_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha512"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+
+	"github.com/AtomSites/atom-components/static"
+)
+
+func get(t *testing.T, path string, header map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	http.StripPrefix("/static/ac/", static.Handler()).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestURL(t *testing.T) {
+	css := static.URL(static.CSS)
+	if !regexp.MustCompile(`^/static/ac/css/atom-components\.[0-9a-f]{10}\.css$`).MatchString(css) {
+		t.Errorf("unexpected CSS URL %q", css)
+	}
+	js := static.URL(static.JS)
+	if !regexp.MustCompile(`^/static/ac/js/atom-components\.[0-9a-f]{10}\.js$`).MatchString(js) {
+		t.Errorf("unexpected JS URL %q", js)
+	}
+	if got := static.URL("css/missing.css"); got != "/static/ac/css/missing.css" {
+		t.Errorf("expected unknown asset to pass through, got %q", got)
+	}
+}
+
+func TestHandlerFingerprinted(t *testing.T) {
+	rec := get(t, static.URL(static.CSS), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if cc := rec.Header().Get("Cache-Control"); !strings.Contains(cc, "immutable") {
+		t.Errorf("expected immutable Cache-Control, got %q", cc)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/css") {
+		t.Errorf("expected text/css, got %q", ct)
+	}
+	if rec.Header().Get("ETag") == "" {
+		t.Error("expected ETag header")
+	}
+	if !strings.Contains(rec.Body.String(), ".ac-modal") {
+		t.Error("expected stylesheet body")
+	}
+}
+
+func TestHandlerPlainPath(t *testing.T) {
+	rec := get(t, "/static/ac/"+static.JS, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc != "no-cache" {
+		t.Errorf("expected no-cache for unhashed path, got %q", cc)
+	}
+}
+
+func TestHandlerIfNoneMatch(t *testing.T) {
+	first := get(t, static.URL(static.JS), nil)
+	etag := first.Header().Get("ETag")
+
+	rec := get(t, static.URL(static.JS), map[string]string{"If-None-Match": etag})
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("expected 304, got %d", rec.Code)
+	}
+	if rec.Body.Len() != 0 {
+		t.Error("expected empty body on 304")
+	}
+}
+
+func TestHandlerNotFound(t *testing.T) {
+	rec := get(t, "/static/ac/css/atom-components.0000000000.css", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestHandlerMethodNotAllowed(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/"+static.CSS, nil)
+	rec := httptest.NewRecorder()
+	static.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", rec.Code)
+	}
+}
+
+func TestHandlerServesMinified(t *testing.T) {
+	rec := get(t, static.URL(static.CSS), nil)
+	src, err := static.Assets.ReadFile(static.CSS)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	if rec.Body.Len() >= len(src) {
+		t.Errorf("expected minified body smaller than %d bytes, got %d", len(src), rec.Body.Len())
+	}
+	if strings.Contains(rec.Body.String(), "/*") {
+		t.Error("expected comments stripped")
+	}
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Error("expected identity encoding without Accept-Encoding")
+	}
+	if rec.Header().Get("Vary") != "Accept-Encoding" {
+		t.Errorf("expected Vary: Accept-Encoding, got %q", rec.Header().Get("Vary"))
+	}
+}
+
+func TestHandlerGzip(t *testing.T) {
+	plain := get(t, static.URL(static.JS), nil)
+	rec := get(t, static.URL(static.JS), map[string]string{"Accept-Encoding": "gzip, deflate"})
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip, got %q", rec.Header().Get("Content-Encoding"))
+	}
+	if rec.Header().Get("ETag") == plain.Header().Get("ETag") {
+		t.Error("expected ETag to differ per encoding")
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip error: %v", err)
+	}
+	body, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("gzip read error: %v", err)
+	}
+	if !bytes.Equal(body, plain.Body.Bytes()) {
+		t.Error("expected gzip variant to decode to the identity body")
+	}
+}
+
+func TestHandlerBrotli(t *testing.T) {
+	rec := get(t, static.URL(static.CSS), map[string]string{"Accept-Encoding": "gzip, br"})
+	if rec.Header().Get("Content-Encoding") != "br" {
+		t.Errorf("expected br preferred, got %q", rec.Header().Get("Content-Encoding"))
+	}
+
+	rec = get(t, static.URL(static.CSS), map[string]string{"Accept-Encoding": "br;q=0, gzip"})
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Errorf("expected gzip when br has q=0, got %q", rec.Header().Get("Content-Encoding"))
+	}
+
+	rec = get(t, static.URL(static.CSS), map[string]string{"Accept-Encoding": "identity"})
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("expected identity, got %q", rec.Header().Get("Content-Encoding"))
+	}
+}
+
+func TestHandlerHidesVariants(t *testing.T) {
+	rec := get(t, "/static/ac/css/atom-components.min.css.gz", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for generated variant, got %d", rec.Code)
+	}
+}
+
+func TestIntegrity(t *testing.T) {
+	rec := get(t, static.URL(static.CSS), nil)
+	sum := sha512.Sum384(rec.Body.Bytes())
+	want := "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+	if got := static.Integrity(static.CSS); got != want {
+		t.Errorf("expected integrity %q to match served body, got %q", want, got)
+	}
+	if got := static.Integrity("css/missing.css"); got != "" {
+		t.Errorf("expected empty integrity for unknown asset, got %q", got)
+	}
+}
+
+func TestHead(t *testing.T) {
+	var buf bytes.Buffer
+	err := static.Head().Render(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html := buf.String()
+
+	if !strings.Contains(html, `href="`+static.URL(static.CSS)+`"`) {
+		t.Error("expected fingerprinted stylesheet URL")
+	}
+	if !strings.Contains(html, `src="`+static.URL(static.JS)+`"`) {
+		t.Error("expected fingerprinted script URL")
+	}
+	if !strings.Contains(html, `integrity="`+static.Integrity(static.CSS)+`"`) {
+		t.Error("expected stylesheet integrity")
+	}
+	if !strings.Contains(html, `integrity="`+static.Integrity(static.JS)+`"`) {
+		t.Error("expected script integrity")
+	}
+	if strings.Contains(html, "nonce") {
+		t.Error("nonce should not be rendered without one in context")
+	}
+}
+
+func TestHeadNonce(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := templ.WithNonce(context.Background(), "r4nd0m")
+	err := static.Head().Render(ctx, &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if n := strings.Count(buf.String(), `nonce="r4nd0m"`); n != 2 {
+		t.Errorf("expected nonce on both tags, found %d", n)
+	}
+}
+
+func render(t *testing.T, ctx context.Context, c templ.Component) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := c.Render(ctx, &buf); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestBundleAssets(t *testing.T) {
+	for _, b := range static.Bundles() {
+		for _, name := range []string{b.CSS(), b.JS()} {
+			if name == "" {
+				continue
+			}
+			if static.Integrity(name) == "" {
+				t.Errorf("bundle %s: missing embedded asset %s", b, name)
+			}
+		}
+	}
+	if static.Card.JS() != "" {
+		t.Error("card should have no script")
+	}
+}
+
+func TestStylesWithoutRegistry(t *testing.T) {
+	html := render(t, context.Background(), static.Styles())
+	if !strings.Contains(html, static.URL(static.CSS)) {
+		t.Error("expected combined stylesheet without a registry")
+	}
+	html = render(t, context.Background(), static.Scripts())
+	if !strings.Contains(html, static.URL(static.JS)) {
+		t.Error("expected combined script without a registry")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	ctx := static.WithRegistry(context.Background())
+	render(t, ctx, static.Use(static.Card))
+	render(t, ctx, static.Use(static.DatePicker))
+
+	html := render(t, ctx, static.Styles())
+	for _, b := range []static.Bundle{static.Card, static.DatePicker, static.Form, static.Modal} {
+		if !strings.Contains(html, static.URL(b.CSS())) {
+			t.Errorf("expected %s stylesheet", b)
+		}
+	}
+	if strings.Contains(html, static.URL(static.Toast.CSS())) {
+		t.Error("unexpected toast stylesheet")
+	}
+	if strings.Contains(html, static.URL(static.CSS)) {
+		t.Error("unexpected combined stylesheet")
+	}
+	if strings.Index(html, static.URL(static.Form.CSS())) > strings.Index(html, static.URL(static.DatePicker.CSS())) {
+		t.Error("expected dependencies before dependents")
+	}
+
+	js := render(t, ctx, static.Scripts())
+	if strings.Contains(js, "card") {
+		t.Error("card has no script")
+	}
+	if !strings.Contains(js, "js/components/core.") {
+		t.Error("expected shared core script")
+	}
+	if strings.Index(js, "js/components/core.") > strings.Index(js, "js/components/datepicker.") {
+		t.Error("expected core script first")
+	}
+
+	if again := render(t, ctx, static.Styles()); again != "" {
+		t.Errorf("expected bundles emitted once, got %q", again)
+	}
+}
+
+func TestRequireBeforeRender(t *testing.T) {
+	ctx := static.WithRegistry(context.Background())
+	static.Require(ctx, static.Toast)
+	html := render(t, ctx, static.Styles())
+	if !strings.Contains(html, static.URL(static.Toast.CSS())) {
+		t.Error("expected toast stylesheet")
+	}
+
+	render(t, ctx, static.Use(static.Toast, static.Card))
+	html = render(t, ctx, static.Styles())
+	if strings.Contains(html, "toast") {
+		t.Error("toast stylesheet should not be emitted twice")
+	}
+	if !strings.Contains(html, static.URL(static.Card.CSS())) {
+		t.Error("expected late-registered card stylesheet")
+	}
+}
+
+func TestInlineStyles(t *testing.T) {
+	ctx := templ.WithNonce(static.WithRegistry(context.Background()), "abc")
+	static.Require(ctx, static.Card)
+	html := render(t, ctx, static.InlineStyles())
+	if !strings.HasPrefix(html, `<style nonce="abc">`) {
+		t.Errorf("expected nonce on inline style, got %.40q", html)
+	}
+	if !strings.Contains(html, ".ac-pricing-card{") {
+		t.Error("expected minified card CSS inlined")
+	}
+	if strings.Contains(html, ".ac-modal") {
+		t.Error("unexpected modal CSS")
+	}
+	if again := render(t, ctx, static.InlineStyles()); again != "" {
+		t.Error("expected nothing once inlined")
+	}
+}
+
+func TestCombinedUpToDate(t *testing.T) {
+	for _, combined := range []string{static.CSS, static.JS} {
+		all, err := static.Assets.ReadFile(combined)
+		if err != nil {
+			t.Fatalf("read error: %v", err)
+		}
+		for _, b := range static.Bundles() {
+			name := b.CSS()
+			if strings.HasSuffix(combined, ".js") {
+				name = b.JS()
+			}
+			if name == "" {
+				continue
+			}
+			part, err := static.Assets.ReadFile(name)
+			if err != nil {
+				t.Fatalf("read error: %v", err)
+			}
+			if !bytes.Contains(all, part) {
+				t.Errorf("%s is stale: run go generate ./static (%s changed)", combined, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package theme
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// rgba is a parsed CSS color with channels in 0–255 and alpha in 0–1.
+type rgba struct {
+	r, g, b float64
+	a       float64
+}
+
+// parseColor understands hex (#rgb, #rgba, #rrggbb, #rrggbbaa), rgb(),
+// rgba(), hsl(), hsla() and "transparent".
+func parseColor(s string) (rgba, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch {
+	case s == "transparent":
+		return rgba{}, nil
+	case strings.HasPrefix(s, "#"):
+		return parseHex(s[1:])
+	case strings.HasPrefix(s, "rgb(") || strings.HasPrefix(s, "rgba("):
+		args, err := colorArgs(s)
+		if err != nil {
+			return rgba{}, err
+		}
+		var c rgba
+		if c.r, err = channel(args[0], 255); err != nil {
+			return rgba{}, err
+		}
+		if c.g, err = channel(args[1], 255); err != nil {
+			return rgba{}, err
+		}
+		if c.b, err = channel(args[2], 255); err != nil {
+			return rgba{}, err
+		}
+		c.a, err = alpha(args)
+		return c, err
+	case strings.HasPrefix(s, "hsl(") || strings.HasPrefix(s, "hsla("):
+		args, err := colorArgs(s)
+		if err != nil {
+			return rgba{}, err
+		}
+		h, err := strconv.ParseFloat(strings.TrimSuffix(args[0], "deg"), 64)
+		if err != nil || math.IsNaN(h) || math.IsInf(h, 0) {
+			return rgba{}, fmt.Errorf("invalid hue %q", args[0])
+		}
+		sat, err := channel(args[1], 1)
+		if err != nil {
+			return rgba{}, err
+		}
+		light, err := channel(args[2], 1)
+		if err != nil {
+			return rgba{}, err
+		}
+		c := fromHSL(h, sat, light)
+		c.a, err = alpha(args)
+		return c, err
+	}
+	return rgba{}, fmt.Errorf("unrecognized color %q", s)
+}
+
+func parseHex(h string) (rgba, error) {
+	switch len(h) {
+	case 3, 4:
+		var long strings.Builder
+		for _, c := range h {
+			long.WriteRune(c)
+			long.WriteRune(c)
+		}
+		h = long.String()
+	case 6, 8:
+	default:
+		return rgba{}, fmt.Errorf("invalid hex color #%s", h)
+	}
+	if len(h) == 6 {
+		h += "ff"
+	}
+	v, err := strconv.ParseUint(h, 16, 32)
+	if err != nil {
+		return rgba{}, fmt.Errorf("invalid hex color #%s", h)
+	}
+	return rgba{
+		r: float64(v >> 24 & 0xff),
+		g: float64(v >> 16 & 0xff),
+		b: float64(v >> 8 & 0xff),
+		a: float64(v&0xff) / 255,
+	}, nil
+}
+
+// colorArgs splits "fn(a, b, c[, d])" (or the space/slash syntax) into its
+// arguments.
+func colorArgs(s string) ([]string, error) {
+	open, end := strings.IndexByte(s, '('), strings.LastIndexByte(s, ')')
+	if open < 0 || end != len(s)-1 {
+		return nil, fmt.Errorf("malformed color %q", s)
+	}
+	args := strings.FieldsFunc(s[open+1:end], func(r rune) bool {
+		return r == ',' || r == ' ' || r == '/'
+	})
+	if len(args) != 3 && len(args) != 4 {
+		return nil, fmt.Errorf("malformed color %q", s)
+	}
+	return args, nil
+}
+
+// channel parses a number or percentage, scaling percentages to max.
+func channel(s string, max float64) (float64, error) {
+	scale := 1.0
+	if strings.HasSuffix(s, "%") {
+		s, scale = strings.TrimSuffix(s, "%"), max/100
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || v*scale < 0 || v*scale > max {
+		return 0, fmt.Errorf("invalid color component %q", s)
+	}
+	return v * scale, nil
+}
+
+func alpha(args []string) (float64, error) {
+	if len(args) < 4 {
+		return 1, nil
+	}
+	return channel(args[3], 1)
+}
+
+// String formats the color as #rrggbb when opaque, rgba() otherwise.
+func (c rgba) String() string {
+	r, g, b := math.Round(c.r), math.Round(c.g), math.Round(c.b)
+	if c.a >= 1 {
+		return fmt.Sprintf("#%02x%02x%02x", int(r), int(g), int(b))
+	}
+	return fmt.Sprintf("rgba(%d, %d, %d, %s)", int(r), int(g), int(b),
+		strconv.FormatFloat(math.Round(c.a*100)/100, 'f', -1, 64))
+}
+
+func (c rgba) withAlpha(a float64) rgba {
+	c.a = a
+	return c
+}
+
+// hsl returns hue in degrees and saturation/lightness in 0–1.
+func (c rgba) hsl() (h, s, l float64) {
+	r, g, b := c.r/255, c.g/255, c.b/255
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l = (max + min) / 2
+	if max == min {
+		return 0, 0, l
+	}
+	d := max - min
+	if l > 0.5 {
+		s = d / (2 - max - min)
+	} else {
+		s = d / (max + min)
+	}
+	switch max {
+	case r:
+		h = (g - b) / d
+		if g < b {
+			h += 6
+		}
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	return h * 60, s, l
+}
+
+func fromHSL(h, s, l float64) rgba {
+	h = math.Mod(math.Mod(h, 360)+360, 360) / 360
+	if s == 0 {
+		return rgba{r: l * 255, g: l * 255, b: l * 255, a: 1}
+	}
+	var q float64
+	if l < 0.5 {
+		q = l * (1 + s)
+	} else {
+		q = l + s - l*s
+	}
+	p := 2*l - q
+	return rgba{
+		r: hueToRGB(p, q, h+1.0/3) * 255,
+		g: hueToRGB(p, q, h) * 255,
+		b: hueToRGB(p, q, h-1.0/3) * 255,
+		a: 1,
+	}
+}
+
+func hueToRGB(p, q, t float64) float64 {
+	if t < 0 {
+		t++
+	}
+	if t > 1 {
+		t--
+	}
+	switch {
+	case t < 1.0/6:
+		return p + (q-p)*6*t
+	case t < 1.0/2:
+		return q
+	case t < 2.0/3:
+		return p + (q-p)*(2.0/3-t)*6
+	}
+	return p
+}
+
+// validLength accepts a non-negative CSS length such as "16px", "1.5rem"
+// or "0".
+func validLength(s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "0" {
+		return true
+	}
+	for _, unit := range []string{"px", "rem", "em", "vh", "vw"} {
+		if num, ok := strings.CutSuffix(s, unit); ok {
+			v, err := strconv.ParseFloat(num, 64)
+			return err == nil && v >= 0 && !math.IsInf(v, 0)
+		}
+	}
+	return false
+}
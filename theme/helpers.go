@@ -0,0 +1,229 @@
+package theme
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// Validate checks that every variable is set and parses. It returns problems
+// keyed by CSS variable name (e.g. "--accent"), or nil if the theme is
+// complete.
+func (t Theme) Validate() map[string]string {
+	var errs map[string]string
+	for _, v := range t.vars() {
+		if msg := v.check(); msg != "" {
+			if errs == nil {
+				errs = make(map[string]string)
+			}
+			errs[v.name] = msg
+		}
+	}
+	return errs
+}
+
+// Default returns the palette atom-quickstart ships with.
+func Default() Theme {
+	t, _ := FromAccent("#b8963e")
+	return t
+}
+
+// FromAccent derives a complete dark glass-morphism palette from a single
+// brand color. Neutrals are tinted with the accent's hue so the page feels
+// of a piece.
+func FromAccent(accent string) (Theme, error) {
+	c, err := parseColor(accent)
+	if err != nil {
+		return Theme{}, err
+	}
+	c.a = 1
+	h, s, l := c.hsl()
+	white := rgba{r: 255, g: 255, b: 255}
+	return Theme{
+		Accent:           c.String(),
+		AccentDark:       fromHSL(h, s, l*0.75).String(),
+		AccentLight:      fromHSL(h, s, l+(1-l)*0.35).String(),
+		BgCard:           fromHSL(h, 0.08, 0.08).String(),
+		BgCardHover:      fromHSL(h, 0.08, 0.12).String(),
+		TextBody:         fromHSL(h, 0.08, 0.66).String(),
+		TextWhite:        fromHSL(h, 0.1, 0.96).String(),
+		GlassBg:          white.withAlpha(0.04).String(),
+		GlassBgHover:     white.withAlpha(0.07).String(),
+		GlassBorder:      white.withAlpha(0.08).String(),
+		GlassBorderHover: c.withAlpha(0.3).String(),
+		GlassBlur:        "16px",
+		BorderSubtle:     c.withAlpha(0.2).String(),
+		BorderCard:       white.withAlpha(0.06).String(),
+		Danger:           "#ef4444",
+	}, nil
+}
+
+// LightFromAccent is FromAccent for a light background: the same accent
+// shades over near-white cards and dark text.
+func LightFromAccent(accent string) (Theme, error) {
+	t, err := FromAccent(accent)
+	if err != nil {
+		return Theme{}, err
+	}
+	c, _ := parseColor(t.Accent)
+	h, _, _ := c.hsl()
+	black := rgba{}
+	t.BgCard = fromHSL(h, 0.2, 0.99).String()
+	t.BgCardHover = fromHSL(h, 0.2, 0.96).String()
+	t.TextBody = fromHSL(h, 0.1, 0.35).String()
+	t.TextWhite = fromHSL(h, 0.15, 0.1).String()
+	t.GlassBg = black.withAlpha(0.03).String()
+	t.GlassBgHover = black.withAlpha(0.05).String()
+	t.GlassBorder = black.withAlpha(0.08).String()
+	t.GlassBorderHover = c.withAlpha(0.4).String()
+	t.BorderSubtle = c.withAlpha(0.25).String()
+	t.BorderCard = black.withAlpha(0.06).String()
+	t.Danger = "#dc2626"
+	return t, nil
+}
+
+// SchemeFromAccent derives both palettes from one brand color.
+func SchemeFromAccent(accent string) (Scheme, error) {
+	dark, err := FromAccent(accent)
+	if err != nil {
+		return Scheme{}, err
+	}
+	light, err := LightFromAccent(accent)
+	if err != nil {
+		return Scheme{}, err
+	}
+	return Scheme{Light: light, Dark: dark}, nil
+}
+
+// DefaultScheme returns the light and dark variants of Default.
+func DefaultScheme() Scheme {
+	s, _ := SchemeFromAccent(Default().Accent)
+	return s
+}
+
+// FromRequest returns the Mode stored in the CookieName cookie, or System
+// when it is absent or unrecognized.
+func FromRequest(r *http.Request) Mode {
+	c, err := r.Cookie(CookieName)
+	if err != nil {
+		return System
+	}
+	return resolveMode(Mode(c.Value))
+}
+
+// HTMLAttrs returns the data-theme attribute to spread on <html> so the
+// page renders in the chosen mode on first paint:
+//
+//	<html lang="en" { theme.HTMLAttrs(theme.FromRequest(r))... }>
+//
+// System renders no attribute and leaves the choice to prefers-color-scheme.
+func HTMLAttrs(m Mode) templ.Attributes {
+	switch m {
+	case Light, Dark:
+		return templ.Attributes{"data-theme": string(m)}
+	}
+	return templ.Attributes{}
+}
+
+func resolveMode(m Mode) Mode {
+	switch m {
+	case Light, Dark:
+		return m
+	}
+	return System
+}
+
+func modeLabel(m Mode) string {
+	switch resolveMode(m) {
+	case Light:
+		return "Light"
+	case Dark:
+		return "Dark"
+	}
+	return "System"
+}
+
+// variable is one entry of the CSS variable contract.
+type variable struct {
+	name     string
+	value    string
+	color    bool
+	optional bool // components have a fallback, so it may be left empty
+}
+
+// vars lists t's values in contract order.
+func (t Theme) vars() []variable {
+	return []variable{
+		{"--accent", t.Accent, true, false},
+		{"--accent-dark", t.AccentDark, true, false},
+		{"--accent-light", t.AccentLight, true, false},
+		{"--bg-card", t.BgCard, true, false},
+		{"--bg-card-hover", t.BgCardHover, true, false},
+		{"--text-body", t.TextBody, true, false},
+		{"--text-white", t.TextWhite, true, false},
+		{"--glass-bg", t.GlassBg, true, false},
+		{"--glass-bg-hover", t.GlassBgHover, true, false},
+		{"--glass-border", t.GlassBorder, true, false},
+		{"--glass-border-hover", t.GlassBorderHover, true, false},
+		{"--glass-blur", t.GlassBlur, false, false},
+		{"--border-subtle", t.BorderSubtle, true, false},
+		{"--border-card", t.BorderCard, true, false},
+		{"--danger", t.Danger, true, true},
+	}
+}
+
+// check returns a problem description for v, or "" if it is valid.
+func (v variable) check() string {
+	switch {
+	case strings.TrimSpace(v.value) == "" && v.optional:
+		return ""
+	case strings.TrimSpace(v.value) == "":
+		return "missing"
+	case !v.color && !validLength(v.value):
+		return fmt.Sprintf("invalid length %q", v.value)
+	case v.color:
+		if _, err := parseColor(v.value); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+// declarations renders the valid variables of t as "name: value;" pairs.
+func (t Theme) declarations() string {
+	var b strings.Builder
+	for _, v := range t.vars() {
+		if v.check() != "" || strings.TrimSpace(v.value) == "" {
+			continue
+		}
+		b.WriteString(v.name)
+		b.WriteString(": ")
+		b.WriteString(strings.TrimSpace(v.value))
+		b.WriteString("; ")
+	}
+	return b.String()
+}
+
+func styleBlock(ctx context.Context, selector string, t Theme) string {
+	return openStyle(ctx) + selector + " { " + t.declarations() + "}</style>"
+}
+
+func schemeBlock(ctx context.Context, s Scheme) string {
+	light, dark := s.Light.declarations(), s.Dark.declarations()
+	return openStyle(ctx) +
+		":root { color-scheme: dark; " + dark + "} " +
+		"@media (prefers-color-scheme: light) { :root:not([data-theme=\"dark\"]) { color-scheme: light; " + light + "} } " +
+		":root[data-theme=\"light\"] { color-scheme: light; " + light + "} " +
+		":root[data-theme=\"dark\"] { color-scheme: dark; " + dark + "}" +
+		"</style>"
+}
+
+func openStyle(ctx context.Context) string {
+	if nonce := templ.GetNonce(ctx); nonce != "" {
+		return `<style nonce="` + templ.EscapeString(nonce) + `">`
+	}
+	return "<style>"
+}
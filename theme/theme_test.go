@@ -0,0 +1,243 @@
+package theme_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+
+	"github.com/AtomSites/atom-components/theme"
+)
+
+var contract = []string{
+	"--accent", "--accent-dark", "--accent-light", "--bg-card", "--bg-card-hover",
+	"--text-body", "--text-white", "--glass-bg", "--glass-bg-hover", "--glass-border",
+	"--glass-border-hover", "--glass-blur", "--border-subtle", "--border-card",
+	"--danger",
+}
+
+func TestStyle(t *testing.T) {
+	var buf bytes.Buffer
+	err := theme.Style(theme.Default()).Render(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html := buf.String()
+
+	if !strings.HasPrefix(html, "<style>:root {") {
+		t.Errorf("expected :root style block, got %.40q", html)
+	}
+	for _, name := range contract {
+		if !strings.Contains(html, name+": ") {
+			t.Errorf("expected %s declaration", name)
+		}
+	}
+}
+
+func TestStyleNonce(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := templ.WithNonce(context.Background(), "n0nce")
+	err := theme.Style(theme.Default()).Render(ctx, &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), `<style nonce="n0nce">`) {
+		t.Error("expected nonce on style element")
+	}
+}
+
+func TestStyleSkipsInvalidValues(t *testing.T) {
+	th := theme.Default()
+	th.Accent = "red;}</style><script>alert(1)</script>"
+	var buf bytes.Buffer
+	err := theme.Style(th).Render(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html := buf.String()
+
+	if strings.Contains(html, "<script>") {
+		t.Error("invalid value must not be rendered")
+	}
+	if strings.Contains(html, "--accent: ") {
+		t.Error("expected invalid --accent to be left out")
+	}
+	if !strings.Contains(html, "--accent-dark: ") {
+		t.Error("expected valid variables to still render")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if errs := theme.Default().Validate(); errs != nil {
+		t.Errorf("expected default theme to be valid, got %v", errs)
+	}
+
+	th := theme.Default()
+	th.BgCard = ""
+	th.TextBody = "#12345"
+	th.GlassBorder = "rgba(255, 255, 255, 2)"
+	th.GlassBlur = "blurry"
+	errs := th.Validate()
+	for _, name := range []string{"--bg-card", "--text-body", "--glass-border", "--glass-blur"} {
+		if errs[name] == "" {
+			t.Errorf("expected error for %s", name)
+		}
+	}
+	if len(errs) != 4 {
+		t.Errorf("expected 4 errors, got %v", errs)
+	}
+	if errs["--bg-card"] != "missing" {
+		t.Errorf("expected missing, got %q", errs["--bg-card"])
+	}
+
+	// --danger was added later and has a CSS fallback, so it may be left out
+	th = theme.Default()
+	th.Danger = ""
+	if errs := th.Validate(); errs != nil {
+		t.Errorf("expected a theme without Danger to be valid, got %v", errs)
+	}
+	var buf bytes.Buffer
+	if err := theme.Style(th).Render(context.Background(), &buf); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if strings.Contains(buf.String(), "--danger") {
+		t.Errorf("expected no --danger declaration, got %s", buf.String())
+	}
+}
+
+func TestValidateColorFormats(t *testing.T) {
+	for _, c := range []string{"#fff", "#ffff", "#B8963E", "#b8963e80", "rgb(1, 2, 3)",
+		"rgba(1, 2, 3, 0.5)", "rgb(10% 20% 30% / 50%)", "hsl(40, 50%, 48%)", "hsla(40deg, 50%, 48%, 0.2)", "transparent"} {
+		th := theme.Default()
+		th.Accent = c
+		if errs := th.Validate(); errs != nil {
+			t.Errorf("expected %q to be valid, got %v", c, errs)
+		}
+	}
+	for _, c := range []string{"red", "#ggg", "rgb(1, 2)", "rgb(256, 0, 0)", "hsl(NaN, 1%, 1%)", "rgb(1,2,3"} {
+		th := theme.Default()
+		th.Accent = c
+		if th.Validate()["--accent"] == "" {
+			t.Errorf("expected %q to be rejected", c)
+		}
+	}
+}
+
+func TestFromAccent(t *testing.T) {
+	th, err := theme.FromAccent("#3B82F6")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if errs := th.Validate(); errs != nil {
+		t.Errorf("expected generated palette to be valid, got %v", errs)
+	}
+	if th.Accent != "#3b82f6" {
+		t.Errorf("expected accent to be normalized, got %q", th.Accent)
+	}
+	if th.AccentDark == th.Accent || th.AccentLight == th.Accent {
+		t.Error("expected distinct dark and light shades")
+	}
+	if th.BorderSubtle != "rgba(59, 130, 246, 0.2)" {
+		t.Errorf("expected accent-tinted subtle border, got %q", th.BorderSubtle)
+	}
+
+	if _, err := theme.FromAccent("not-a-color"); err == nil {
+		t.Error("expected error for invalid accent")
+	}
+}
+
+func TestSchemeStyle(t *testing.T) {
+	s, err := theme.SchemeFromAccent("#3b82f6")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if errs := s.Light.Validate(); errs != nil {
+		t.Errorf("expected light palette to be valid, got %v", errs)
+	}
+	if s.Light.BgCard == s.Dark.BgCard {
+		t.Error("expected light and dark card backgrounds to differ")
+	}
+
+	var buf bytes.Buffer
+	if err := theme.SchemeStyle(s).Render(context.Background(), &buf); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html := buf.String()
+
+	if !strings.Contains(html, "@media (prefers-color-scheme: light)") {
+		t.Error("expected prefers-color-scheme media query")
+	}
+	if !strings.Contains(html, `:root[data-theme="light"]`) {
+		t.Error("expected explicit light override")
+	}
+	if !strings.Contains(html, `:root[data-theme="dark"]`) {
+		t.Error("expected explicit dark override")
+	}
+	if !strings.Contains(html, "--bg-card: "+s.Light.BgCard) || !strings.Contains(html, "--bg-card: "+s.Dark.BgCard) {
+		t.Error("expected both palettes")
+	}
+}
+
+func TestToggle(t *testing.T) {
+	var buf bytes.Buffer
+	if err := theme.Toggle(theme.Dark).Render(context.Background(), &buf); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html := buf.String()
+
+	if !strings.Contains(html, "data-ac-theme-toggle") {
+		t.Error("expected data-ac-theme-toggle attribute")
+	}
+	if !strings.Contains(html, `data-ac-theme-mode="dark"`) {
+		t.Error("expected current mode")
+	}
+	if !strings.Contains(html, `data-ac-theme-cookie="`+theme.CookieName+`"`) {
+		t.Error("expected cookie name for the script")
+	}
+	if !strings.Contains(html, `aria-label="Theme: Dark"`) {
+		t.Error("expected accessible label")
+	}
+
+	buf.Reset()
+	if err := theme.Toggle("bogus").Render(context.Background(), &buf); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `data-ac-theme-mode="system"`) {
+		t.Error("expected unknown mode to fall back to system")
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	tests := []struct {
+		cookie string
+		want   theme.Mode
+	}{
+		{"", theme.System},
+		{"light", theme.Light},
+		{"dark", theme.Dark},
+		{"system", theme.System},
+		{"purple", theme.System},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: theme.CookieName, Value: tt.cookie})
+		}
+		if got := theme.FromRequest(req); got != tt.want {
+			t.Errorf("cookie %q: expected %q, got %q", tt.cookie, tt.want, got)
+		}
+	}
+}
+
+func TestHTMLAttrs(t *testing.T) {
+	if got := theme.HTMLAttrs(theme.Light)["data-theme"]; got != "light" {
+		t.Errorf("expected data-theme=light, got %v", got)
+	}
+	if _, ok := theme.HTMLAttrs(theme.System)["data-theme"]; ok {
+		t.Error("system mode should not set data-theme")
+	}
+}
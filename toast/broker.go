@@ -0,0 +1,268 @@
+package toast
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// subscriberBuffer is how many events a connection may fall behind
+	// before it is dropped. The browser reconnects and catches up from the
+	// replay history.
+	subscriberBuffer = 16
+
+	// replayLimit and replayWindow bound the per-user history kept for
+	// reconnecting clients.
+	replayLimit  = 32
+	replayWindow = time.Minute
+)
+
+// Broker pushes toasts to a user's open pages over Server-Sent Events.
+// Publish from anywhere (a background job, another request); every page
+// the user has open with a Container whose StreamURL points at Handler
+// shows the toast. Create one with NewBroker.
+type Broker struct {
+	// KeepAlive is how often an idle stream sends a comment so proxies
+	// don't time it out. Set it before serving; 0 disables.
+	KeepAlive time.Duration
+
+	userID func(*http.Request) string
+	epoch  string // distinguishes event IDs across broker restarts
+
+	mu      sync.Mutex
+	seq     uint64
+	subs    map[string]map[*subscriber]struct{}
+	history map[string][]event
+	pruned  time.Time
+	closed  bool
+}
+
+type subscriber struct {
+	ch chan event
+}
+
+type event struct {
+	id   string
+	seq  uint64
+	data []byte
+	at   time.Time
+}
+
+// NewBroker returns a Broker that uses userID to tell whose stream a request
+// is for, typically by reading the session. Requests for which it returns
+// "" are rejected with 401.
+func NewBroker(userID func(r *http.Request) string) *Broker {
+	return &Broker{
+		KeepAlive: 30 * time.Second,
+		userID:    userID,
+		epoch:     strconv.FormatInt(time.Now().UnixNano(), 36),
+		subs:      map[string]map[*subscriber]struct{}{},
+		history:   map[string][]event{},
+	}
+}
+
+// Publish sends a toast to every open stream of user. It never blocks: a
+// connection too far behind is closed and catches up when it reconnects.
+// Messages for users with no open stream are kept briefly for replay.
+func (b *Broker) Publish(user, message string, level Level) {
+	data, _ := json.Marshal(Message{Text: message, Level: level})
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	now := time.Now()
+	b.prune(now)
+	b.seq++
+	ev := event{id: b.epoch + "-" + strconv.FormatUint(b.seq, 10), seq: b.seq, data: data, at: now}
+
+	h := append(b.history[user], ev)
+	if len(h) > replayLimit {
+		h = h[len(h)-replayLimit:]
+	}
+	b.history[user] = h
+
+	for s := range b.subs[user] {
+		select {
+		case s.ch <- ev:
+		default:
+			b.drop(user, s)
+		}
+	}
+}
+
+// Close ends every open stream and makes Publish a no-op, so that
+// http.Server.Shutdown isn't held up by long-lived connections.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for user, subs := range b.subs {
+		for s := range subs {
+			b.drop(user, s)
+		}
+	}
+}
+
+// Handler returns the SSE endpoint. Mount it on a GET route and set the
+// same path as ContainerOptions.StreamURL.
+func (b *Broker) Handler() http.Handler {
+	return http.HandlerFunc(b.serve)
+}
+
+func (b *Broker) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user := b.userID(r)
+	if user == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	rc := http.NewResponseController(w)
+	// The stream outlives any server WriteTimeout; not every writer can
+	// change it, which is fine.
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	s, missed, ok := b.subscribe(user, r.Header.Get("Last-Event-ID"))
+	if !ok {
+		http.Error(w, "stream closed", http.StatusServiceUnavailable)
+		return
+	}
+	defer b.unsubscribe(user, s)
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprint(w, ": connected\n\n"); err != nil {
+		return
+	}
+	for _, ev := range missed {
+		if writeEvent(w, ev) != nil {
+			return
+		}
+	}
+	if rc.Flush() != nil {
+		return
+	}
+
+	var tick <-chan time.Time
+	if b.KeepAlive > 0 {
+		t := time.NewTicker(b.KeepAlive)
+		defer t.Stop()
+		tick = t.C
+	}
+	ctx := r.Context()
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return
+		case ev, open := <-s.ch:
+			if !open {
+				return
+			}
+			err = writeEvent(w, ev)
+		case <-tick:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// subscribe registers a stream for user and returns the history events
+// after lastID. Both happen under one lock, so nothing is missed or sent
+// twice between replay and live events.
+func (b *Broker) subscribe(user, lastID string) (*subscriber, []event, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, nil, false
+	}
+	s := &subscriber{ch: make(chan event, subscriberBuffer)}
+	if b.subs[user] == nil {
+		b.subs[user] = map[*subscriber]struct{}{}
+	}
+	b.subs[user][s] = struct{}{}
+
+	var missed []event
+	if seq, ok := b.parseID(lastID); ok {
+		for _, ev := range b.history[user] {
+			if ev.seq > seq {
+				missed = append(missed, ev)
+			}
+		}
+	}
+	return s, missed, true
+}
+
+func (b *Broker) unsubscribe(user string, s *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.drop(user, s)
+}
+
+// drop removes s and closes its channel, which ends its handler. Callers
+// hold b.mu.
+func (b *Broker) drop(user string, s *subscriber) {
+	subs := b.subs[user]
+	if _, ok := subs[s]; !ok {
+		return
+	}
+	delete(subs, s)
+	close(s.ch)
+	if len(subs) == 0 {
+		delete(b.subs, user)
+	}
+}
+
+// prune forgets history older than replayWindow, at most once per window.
+// Callers hold b.mu.
+func (b *Broker) prune(now time.Time) {
+	if now.Sub(b.pruned) < replayWindow {
+		return
+	}
+	b.pruned = now
+	for user, h := range b.history {
+		i := 0
+		for i < len(h) && now.Sub(h[i].at) > replayWindow {
+			i++
+		}
+		if i == len(h) {
+			delete(b.history, user)
+		} else {
+			b.history[user] = h[i:]
+		}
+	}
+}
+
+// parseID returns the sequence number of an event ID issued by this broker.
+// IDs from before a restart carry another epoch and replay nothing.
+func (b *Broker) parseID(id string) (uint64, bool) {
+	epoch, seq, ok := strings.Cut(id, "-")
+	if !ok || epoch != b.epoch {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	return n, err == nil
+}
+
+func writeEvent(w http.ResponseWriter, ev event) error {
+	_, err := fmt.Fprintf(w, "id: %s\nevent: toast\ndata: %s\n\n", ev.id, ev.data)
+	return err
+}
@@ -0,0 +1,243 @@
+package toast
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/a-h/templ"
+)
+
+// FlashCookieName is the cookie FlashMiddleware keeps queued messages in.
+const FlashCookieName = "ac_flash"
+
+// maxFlashes caps the queue so the cookie stays well under browser limits.
+const maxFlashes = 10
+
+// Message is a toast queued with Flash or Trigger.
+type Message struct {
+	Text  string `json:"message"`
+	Level Level  `json:"level"`
+}
+
+// flashStore holds the request's queued messages: those that arrived in
+// the cookie plus those added with Flash.
+type flashStore struct {
+	mu       sync.Mutex
+	messages []Message
+	incoming bool // the request carried a flash cookie
+}
+
+type flashKey struct{}
+
+// Flash queues a toast for the next page that renders Flashes, typically
+// the target of a POST-redirect-GET. It does nothing unless the request
+// went through FlashMiddleware.
+func Flash(ctx context.Context, message string, level Level) {
+	s, _ := ctx.Value(flashKey{}).(*flashStore)
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, Message{Text: message, Level: level})
+	if len(s.messages) > maxFlashes {
+		s.messages = s.messages[len(s.messages)-maxFlashes:]
+	}
+}
+
+// Flashes renders the queued messages as toasts, each at most once. Place
+// it inside Container:
+//
+//	@toast.Container() {
+//		@toast.Flashes(ctx)
+//	}
+func Flashes(ctx context.Context) templ.Component {
+	s, _ := ctx.Value(flashKey{}).(*flashStore)
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if s == nil {
+			return nil
+		}
+		s.mu.Lock()
+		messages := s.messages
+		s.messages = nil
+		s.mu.Unlock()
+		for _, m := range messages {
+			if err := Toast(m.Text, m.Level).Render(ctx, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FlashMiddleware carries Flash messages across requests in a cookie signed
+// with HMAC-SHA256 under secret; use at least 32 random bytes. Messages stay
+// queued through redirects, non-HTML responses and responses without a
+// body, and are delivered by the next successful HTML page, which should
+// render Flashes. Cookies that fail verification are discarded.
+func FlashMiddleware(secret []byte) func(http.Handler) http.Handler {
+	if len(secret) == 0 {
+		panic("toast: FlashMiddleware needs a secret")
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s := &flashStore{}
+			if c, err := r.Cookie(FlashCookieName); err == nil {
+				s.incoming = true
+				s.messages = decodeFlashes(secret, c.Value)
+			}
+			fw := &flashWriter{ResponseWriter: w, r: r, secret: secret, store: s}
+			next.ServeHTTP(fw, r.WithContext(context.WithValue(r.Context(), flashKey{}, s)))
+			fw.finish()
+		})
+	}
+}
+
+// flashWriter writes the flash cookie just before the response headers.
+// Whether the queue was delivered depends on the body, so WriteHeader is
+// held back until the first Write, a flush, or the end of the handler.
+type flashWriter struct {
+	http.ResponseWriter
+	r         *http.Request
+	secret    []byte
+	store     *flashStore
+	status    int // held back by WriteHeader; 0 if not called
+	committed bool
+}
+
+func (w *flashWriter) WriteHeader(status int) {
+	if w.committed || status < 200 {
+		// Informational responses such as 103 Early Hints go straight out
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *flashWriter) Write(b []byte) (int, error) {
+	if !w.committed {
+		if len(b) == 0 {
+			return 0, nil
+		}
+		w.commit(w.delivers(b))
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// FlushError sends the held-back headers; a flush before any body counts
+// as not delivered.
+func (w *flashWriter) FlushError() error {
+	if !w.committed {
+		w.commit(false)
+	}
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *flashWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// finish runs after the handler. A response without a body, such as a 204
+// or an htmx reply with only HX-Redirect, hasn't shown anything, so the
+// queue is passed on.
+func (w *flashWriter) finish() {
+	if !w.committed {
+		w.commit(false)
+	}
+}
+
+// delivers reports whether the response shows the queue: a successful HTML
+// page with a body, whose first bytes are b. Without a Content-Type, the
+// one net/http will sniff from b is used.
+func (w *flashWriter) delivers(b []byte) bool {
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	ct := w.Header().Get("Content-Type")
+	if ct == "" {
+		ct = http.DetectContentType(b)
+	}
+	return status >= 200 && status < 300 && strings.HasPrefix(ct, "text/html")
+}
+
+// commit decides what the browser keeps, then sends the held-back status:
+// a delivered page clears the queue, anything else (redirects, errors, JSON,
+// empty responses) passes it on.
+func (w *flashWriter) commit(delivered bool) {
+	w.committed = true
+	s := w.store
+	s.mu.Lock()
+	messages := s.messages
+	s.mu.Unlock()
+
+	cookie := &http.Cookie{
+		Name:     FlashCookieName,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   w.r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	}
+	switch {
+	case !delivered && len(messages) > 0:
+		cookie.Value = encodeFlashes(w.secret, messages)
+	case s.incoming:
+		cookie.MaxAge = -1
+	default:
+		cookie = nil
+	}
+	if cookie != nil {
+		http.SetCookie(w.ResponseWriter, cookie)
+	}
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+}
+
+// encodeFlashes returns base64url(JSON) "." base64url(HMAC).
+func encodeFlashes(secret []byte, messages []Message) string {
+	payload, _ := json.Marshal(messages)
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(flashMAC(secret, payload))
+}
+
+func decodeFlashes(secret []byte, value string) []Message {
+	data, sig, ok := strings.Cut(value, ".")
+	if !ok {
+		return nil
+	}
+	enc := base64.RawURLEncoding
+	payload, err := enc.DecodeString(data)
+	if err != nil {
+		return nil
+	}
+	mac, err := enc.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, flashMAC(secret, payload)) {
+		return nil
+	}
+	var messages []Message
+	if err := json.Unmarshal(payload, &messages); err != nil {
+		return nil
+	}
+	if len(messages) > maxFlashes {
+		messages = messages[len(messages)-maxFlashes:]
+	}
+	return messages
+}
+
+func flashMAC(secret, payload []byte) []byte {
+	m := hmac.New(sha256.New, secret)
+	m.Write([]byte(FlashCookieName + "\x00"))
+	m.Write(payload)
+	return m.Sum(nil)
+}
@@ -0,0 +1,20 @@
+package toast
+
+import (
+	"strconv"
+	"time"
+)
+
+// durationMillis formats d for data-toast-duration, which the script reads
+// as milliseconds.
+func durationMillis(d time.Duration) string {
+	return strconv.FormatInt(d.Milliseconds(), 10)
+}
+
+func position(p Position) Position {
+	switch p {
+	case TopLeft, BottomRight, BottomLeft, TopCenter, BottomCenter:
+		return p
+	}
+	return TopRight
+}
@@ -1,10 +1,18 @@
 package toast_test
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/a-h/templ"
 
 	"github.com/AtomSites/atom-components/toast"
 )
@@ -20,6 +28,18 @@ func TestContainer(t *testing.T) {
 	if !strings.Contains(html, "ac-toast-container") {
 		t.Error("expected ac-toast-container class")
 	}
+	if !strings.Contains(html, `role="region" aria-label="Notifications" tabindex="-1"`) {
+		t.Error("expected focusable notifications landmark")
+	}
+	if !strings.Contains(html, `aria-live="polite" data-toast-live="polite"`) {
+		t.Error("expected polite live region")
+	}
+	if !strings.Contains(html, `aria-live="assertive" data-toast-live="assertive"`) {
+		t.Error("expected assertive live region")
+	}
+	if strings.Contains(html, "data-toast-shortcut") {
+		t.Error("no shortcut should be bound by default")
+	}
 }
 
 func TestToast(t *testing.T) {
@@ -42,8 +62,8 @@ func TestToast(t *testing.T) {
 			if !strings.Contains(html, "Something happened") {
 				t.Error("expected toast message")
 			}
-			if !strings.Contains(html, `role="alert"`) {
-				t.Error("expected alert role")
+			if strings.Contains(html, "role=") {
+				t.Error("toasts are announced through the container's live regions, not their own role")
 			}
 			if !strings.Contains(html, "data-toast-close") {
 				t.Error("expected close button with data-toast-close")
@@ -51,3 +71,579 @@ func TestToast(t *testing.T) {
 		})
 	}
 }
+
+func TestContainerChildren(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := templ.WithChildren(context.Background(), toast.Toast("Hello", toast.Info))
+	if err := toast.Container().Render(ctx, &buf); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html := buf.String()
+	if !strings.Contains(html, `data-toast-live="assertive"></div><div class="ac-toast ac-toast-info"`) {
+		t.Errorf("expected toast inside the container, got %s", html)
+	}
+}
+
+var flashSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// flashServer saves on POST and redirects; GET renders the flashes.
+func flashServer() http.Handler {
+	return toast.FlashMiddleware(flashSecret)(flashMux())
+}
+
+func flashMux() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /save", func(w http.ResponseWriter, r *http.Request) {
+		toast.Flash(r.Context(), "Saved!", toast.Success)
+		toast.Flash(r.Context(), "<b>sync</b> pending", toast.Warning)
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+	})
+	// The htmx way: no body, the client follows HX-Redirect
+	mux.HandleFunc("POST /htmx", func(w http.ResponseWriter, r *http.Request) {
+		toast.Flash(r.Context(), "Saved!", toast.Success)
+		w.Header().Set("HX-Redirect", "/")
+	})
+	mux.HandleFunc("DELETE /item", func(w http.ResponseWriter, r *http.Request) {
+		toast.Flash(r.Context(), "Deleted", toast.Info)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("GET /api", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := io.WriteString(w, "{}"); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
+		templ.Handler(toast.Flashes(r.Context())).ServeHTTP(w, r)
+	})
+	return mux
+}
+
+func flashCookie(t *testing.T, res *http.Response) *http.Cookie {
+	t.Helper()
+	for _, c := range res.Cookies() {
+		if c.Name == toast.FlashCookieName {
+			return c
+		}
+	}
+	return nil
+}
+
+func serve(h http.Handler, method, path string, c *http.Cookie) *http.Response {
+	r := httptest.NewRequest(method, path, nil)
+	if c != nil {
+		r.AddCookie(c)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Result()
+}
+
+func TestFlashSurvivesRedirect(t *testing.T) {
+	h := flashServer()
+
+	res := serve(h, http.MethodPost, "/save", nil)
+	if res.StatusCode != http.StatusSeeOther {
+		t.Fatalf("status = %d", res.StatusCode)
+	}
+	c := flashCookie(t, res)
+	if c == nil || c.Value == "" {
+		t.Fatal("expected flash cookie on redirect")
+	}
+	if !c.HttpOnly || c.SameSite != http.SameSiteLaxMode {
+		t.Error("expected HttpOnly, SameSite=Lax cookie")
+	}
+	if strings.Contains(c.Value, "Saved") {
+		t.Error("cookie payload should be encoded")
+	}
+
+	// A JSON response in between doesn't use them up
+	res = serve(h, http.MethodGet, "/api", c)
+	if next := flashCookie(t, res); next == nil || next.Value == "" {
+		t.Fatal("expected flashes carried past a non-HTML response")
+	} else {
+		c = next
+	}
+
+	res = serve(h, http.MethodGet, "/", c)
+	body, _ := io.ReadAll(res.Body)
+	html := string(body)
+	if !strings.Contains(html, "Saved!") || !strings.Contains(html, "ac-toast-success") {
+		t.Error("expected success flash rendered")
+	}
+	if !strings.Contains(html, "&lt;b&gt;sync&lt;/b&gt; pending") || !strings.Contains(html, "ac-toast-warning") {
+		t.Error("expected escaped warning flash rendered")
+	}
+	if cleared := flashCookie(t, res); cleared == nil || cleared.MaxAge >= 0 {
+		t.Error("expected flash cookie cleared after rendering")
+	}
+
+	res = serve(h, http.MethodGet, "/", nil)
+	body, _ = io.ReadAll(res.Body)
+	if strings.Contains(string(body), "ac-toast") {
+		t.Error("flashes should render only once")
+	}
+	if flashCookie(t, res) != nil {
+		t.Error("unexpected cookie without flashes")
+	}
+}
+
+func TestFlashSurvivesEmptyResponse(t *testing.T) {
+	h := flashServer()
+	for _, tc := range []struct {
+		method, path string
+		status       int
+		message      string
+	}{
+		{http.MethodPost, "/htmx", http.StatusOK, "Saved!"},
+		{http.MethodDelete, "/item", http.StatusNoContent, "Deleted"},
+	} {
+		res := serve(h, tc.method, tc.path, nil)
+		if res.StatusCode != tc.status {
+			t.Fatalf("%s %s: status = %d", tc.method, tc.path, res.StatusCode)
+		}
+		c := flashCookie(t, res)
+		if c == nil || c.Value == "" {
+			t.Fatalf("%s %s: expected flash cookie on a response without a body", tc.method, tc.path)
+		}
+		body, _ := io.ReadAll(serve(h, http.MethodGet, "/", c).Body)
+		if !strings.Contains(string(body), tc.message) {
+			t.Errorf("%s %s: expected %q rendered on the next page", tc.method, tc.path, tc.message)
+		}
+	}
+}
+
+func TestFlashRejectsTamperedCookie(t *testing.T) {
+	h := flashServer()
+	c := flashCookie(t, serve(h, http.MethodPost, "/save", nil))
+
+	forged := *c
+	data, sig, _ := strings.Cut(c.Value, ".")
+	forged.Value = data[:len(data)-2] + "xx." + sig
+	res := serve(h, http.MethodGet, "/", &forged)
+	body, _ := io.ReadAll(res.Body)
+	if strings.Contains(string(body), "ac-toast") {
+		t.Error("tampered cookie should be ignored")
+	}
+	if cleared := flashCookie(t, res); cleared == nil || cleared.MaxAge >= 0 {
+		t.Error("expected tampered cookie cleared")
+	}
+
+	other := toast.FlashMiddleware([]byte("another secret, also 32 bytes..."))(flashMux())
+	body, _ = io.ReadAll(serve(other, http.MethodGet, "/", c).Body)
+	if strings.Contains(string(body), "Saved!") {
+		t.Error("cookie signed with another secret should be ignored")
+	}
+}
+
+func TestFlashWithoutMiddleware(t *testing.T) {
+	ctx := context.Background()
+	toast.Flash(ctx, "dropped", toast.Info)
+	var buf bytes.Buffer
+	if err := toast.Flashes(ctx).Render(ctx, &buf); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing rendered, got %q", buf.String())
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	w := httptest.NewRecorder()
+	toast.Trigger(w, "Row saved", toast.Success)
+	toast.Trigger(w, "Café closed — 3 items moved", toast.Warning)
+
+	v := w.Header().Get(toast.TriggerHeader)
+	for _, r := range v {
+		if r > 127 {
+			t.Fatalf("header should be ASCII, got %q", v)
+		}
+	}
+	var got []struct {
+		Message string `json:"message"`
+		Level   string `json:"level"`
+	}
+	if err := json.Unmarshal([]byte(v), &got); err != nil {
+		t.Fatalf("header is not JSON: %v (%q)", err, v)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d toasts, want 2", len(got))
+	}
+	if got[0].Message != "Row saved" || got[0].Level != "success" {
+		t.Errorf("first toast = %+v", got[0])
+	}
+	if got[1].Message != "Café closed — 3 items moved" || got[1].Level != "warning" {
+		t.Errorf("second toast = %+v", got[1])
+	}
+}
+
+func TestToastWithOptions(t *testing.T) {
+	var buf bytes.Buffer
+	err := toast.ToastWithOptions("Order #42 shipped", toast.Success, toast.Options{
+		Title:    "Shipped",
+		Duration: 8 * time.Second,
+		Action:   toast.Action{Label: "Track", URL: "/orders/42"},
+		Key:      "order-42",
+	}).Render(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html := buf.String()
+
+	for _, want := range []string{
+		`data-toast-duration="8000"`,
+		`data-toast-key="order-42"`,
+		`<strong class="ac-toast-title">Shipped</strong>`,
+		`<span class="ac-toast-message">Order #42 shipped</span>`,
+		`<a class="ac-toast-action" href="/orders/42">Track</a>`,
+		"data-toast-close",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s", want)
+		}
+	}
+	if strings.Contains(html, "data-toast-persistent") {
+		t.Error("unexpected data-toast-persistent")
+	}
+}
+
+func TestToastWithOptionsPersistentAndEscaped(t *testing.T) {
+	var buf bytes.Buffer
+	err := toast.ToastWithOptions(`<img src=x onerror=alert(1)>`, toast.Error, toast.Options{
+		Persistent: true,
+		Action:     toast.Action{Label: "Retry", URL: "javascript:alert(1)"},
+	}).Render(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html := buf.String()
+
+	if !strings.Contains(html, "data-toast-persistent") {
+		t.Error("expected data-toast-persistent")
+	}
+	if strings.Contains(html, "<img") {
+		t.Error("message must be escaped")
+	}
+	if strings.Contains(html, "javascript:") {
+		t.Error("unsafe action URL must be sanitized")
+	}
+	if strings.Contains(html, "ac-toast-title") || strings.Contains(html, "data-toast-duration") {
+		t.Error("unexpected title or duration without options")
+	}
+}
+
+func TestContainerWithOptions(t *testing.T) {
+	var buf bytes.Buffer
+	err := toast.ContainerWithOptions(toast.ContainerOptions{
+		Position:    toast.BottomCenter,
+		MaxVisible:  3,
+		NewestFirst: true,
+		Duration:    7 * time.Second,
+		Shortcut:    "Ctrl+Shift+N",
+		StreamURL:   "/events/toasts",
+	}).Render(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html := buf.String()
+
+	for _, want := range []string{
+		`data-toast-position="bottom-center"`,
+		`data-toast-max="3"`,
+		`data-toast-order="newest-first"`,
+		`data-toast-duration="7000"`,
+		`data-toast-shortcut="Ctrl+Shift+N"`,
+		`data-toast-stream="/events/toasts"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s", want)
+		}
+	}
+
+	buf.Reset()
+	if err := toast.ContainerWithOptions(toast.ContainerOptions{Position: "middle"}).Render(context.Background(), &buf); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	html = buf.String()
+	if !strings.Contains(html, `data-toast-position="top-right"`) {
+		t.Error("expected unknown position to fall back to top-right")
+	}
+	for _, attr := range []string{"data-toast-max", "data-toast-order", "data-toast-duration", "data-toast-stream"} {
+		if strings.Contains(html, attr) {
+			t.Errorf("unexpected %s", attr)
+		}
+	}
+}
+
+// userFromHeader identifies the user by a test header.
+func userFromHeader(r *http.Request) string {
+	return r.Header.Get("X-User")
+}
+
+// sseEvent is one parsed event from the stream.
+type sseEvent struct {
+	id, name, data string
+}
+
+// stream connects to the broker as user and returns a channel of events.
+// The connection is closed when the test ends.
+func stream(t *testing.T, srv *httptest.Server, user, lastID string) <-chan sseEvent {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	req.Header.Set("X-User", user)
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", res.StatusCode)
+	}
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	events := make(chan sseEvent, 32)
+	go func() {
+		defer res.Body.Close()
+		defer close(events)
+		sc := bufio.NewScanner(res.Body)
+		var ev sseEvent
+		for sc.Scan() {
+			line := sc.Text()
+			switch {
+			case line == "":
+				if ev.name != "" {
+					events <- ev
+				}
+				ev = sseEvent{}
+			case strings.HasPrefix(line, "id: "):
+				ev.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				ev.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				ev.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	return events
+}
+
+func next(t *testing.T, events <-chan sseEvent) sseEvent {
+	t.Helper()
+	select {
+	case ev, ok := <-events:
+		if !ok {
+			t.Fatal("stream ended")
+		}
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	return sseEvent{}
+}
+
+// connected publishes a probe until the stream delivers it, so the
+// subscription is known to be registered.
+func connected(t *testing.T, b *toast.Broker, user string, events <-chan sseEvent) {
+	t.Helper()
+	b.Publish(user, "probe", toast.Info)
+	if ev := next(t, events); !strings.Contains(ev.data, "probe") {
+		t.Fatalf("unexpected first event %+v", ev)
+	}
+}
+
+func TestBrokerPublish(t *testing.T) {
+	b := toast.NewBroker(userFromHeader)
+	srv := httptest.NewServer(b.Handler())
+	defer srv.Close()
+	defer b.Close()
+
+	alice := stream(t, srv, "alice", "")
+	bob := stream(t, srv, "bob", "")
+	connected(t, b, "alice", alice)
+	connected(t, b, "bob", bob)
+
+	b.Publish("alice", "Export finished", toast.Success)
+	b.Publish("alice", `Quota "almost" full`, toast.Warning)
+
+	ev := next(t, alice)
+	if ev.name != "toast" || ev.id == "" {
+		t.Errorf("expected toast event with id, got %+v", ev)
+	}
+	if ev.data != `{"message":"Export finished","level":"success"}` {
+		t.Errorf("data = %s", ev.data)
+	}
+	if ev2 := next(t, alice); ev2.data != `{"message":"Quota \"almost\" full","level":"warning"}` || ev2.id == ev.id {
+		t.Errorf("second event = %+v", ev2)
+	}
+
+	select {
+	case ev := <-bob:
+		t.Errorf("bob received alice's toast: %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestBrokerReplaysAfterReconnect(t *testing.T) {
+	b := toast.NewBroker(userFromHeader)
+	srv := httptest.NewServer(b.Handler())
+	defer srv.Close()
+	defer b.Close()
+
+	first := stream(t, srv, "alice", "")
+	connected(t, b, "alice", first)
+	b.Publish("alice", "one", toast.Info)
+	last := next(t, first).id
+
+	// Missed while disconnected
+	b.Publish("alice", "two", toast.Info)
+	b.Publish("alice", "three", toast.Info)
+
+	again := stream(t, srv, "alice", last)
+	if ev := next(t, again); !strings.Contains(ev.data, `"two"`) {
+		t.Errorf("expected replay of two, got %+v", ev)
+	}
+	if ev := next(t, again); !strings.Contains(ev.data, `"three"`) {
+		t.Errorf("expected replay of three, got %+v", ev)
+	}
+
+	// An ID from another broker (e.g. before a restart) replays nothing
+	other := toast.NewBroker(userFromHeader)
+	osrv := httptest.NewServer(other.Handler())
+	defer osrv.Close()
+	defer other.Close()
+	fresh := stream(t, osrv, "alice", last)
+	other.Publish("alice", "new", toast.Info)
+	if ev := next(t, fresh); !strings.Contains(ev.data, `"new"`) {
+		t.Errorf("expected only new events, got %+v", ev)
+	}
+}
+
+func TestBrokerRejects(t *testing.T) {
+	b := toast.NewBroker(userFromHeader)
+	defer b.Close()
+	h := b.Handler()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous: status = %d, want 401", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set("X-User", "alice")
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST: status = %d, want 405", w.Code)
+	}
+}
+
+func TestBrokerClientDisconnect(t *testing.T) {
+	b := toast.NewBroker(userFromHeader)
+	defer b.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	r.Header.Set("X-User", "alice")
+	done := make(chan struct{})
+	go func() {
+		b.Handler().ServeHTTP(httptest.NewRecorder(), r)
+		close(done)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler did not return after the client went away")
+	}
+	// Publishing to a user with no streams must not block
+	b.Publish("alice", "nobody listening", toast.Info)
+}
+
+// blockingWriter stalls every write until released, like a client that
+// stopped reading.
+type blockingWriter struct {
+	header  http.Header
+	started chan struct{}
+	release chan struct{}
+}
+
+func (w *blockingWriter) Header() http.Header { return w.header }
+func (w *blockingWriter) WriteHeader(int)     { close(w.started) }
+func (w *blockingWriter) Flush()              {}
+func (w *blockingWriter) Write(b []byte) (int, error) {
+	if strings.Contains(string(b), "event: toast") {
+		<-w.release
+	}
+	return len(b), nil
+}
+
+func TestBrokerSlowConsumer(t *testing.T) {
+	b := toast.NewBroker(userFromHeader)
+	defer b.Close()
+
+	w := &blockingWriter{header: http.Header{}, started: make(chan struct{}), release: make(chan struct{})}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-User", "alice")
+	done := make(chan struct{})
+	go func() {
+		b.Handler().ServeHTTP(w, r)
+		close(done)
+	}()
+	<-w.started
+
+	// The handler blocks on the first write; the rest fill its buffer and
+	// then get it dropped. Publish itself must never wait.
+	published := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			b.Publish("alice", "burst", toast.Info)
+		}
+		close(published)
+	}()
+	select {
+	case <-published:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Publish blocked on a slow consumer")
+	}
+
+	close(w.release)
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("dropped stream's handler did not return")
+	}
+}
+
+func TestBrokerClose(t *testing.T) {
+	b := toast.NewBroker(userFromHeader)
+	srv := httptest.NewServer(b.Handler())
+	defer srv.Close()
+
+	events := stream(t, srv, "alice", "")
+	connected(t, b, "alice", events)
+	b.Close()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("unexpected event after Close")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream not closed by Close")
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-User", "alice")
+	b.Handler().ServeHTTP(w, r)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("after Close: status = %d, want 503", w.Code)
+	}
+}
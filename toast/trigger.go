@@ -0,0 +1,47 @@
+package toast
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// TriggerHeader is the response header Trigger writes. The toast script
+// reads it from htmx responses and from requests made with acFetch.
+const TriggerHeader = "X-AC-Toast"
+
+// Trigger asks the page to show a toast when it receives this response,
+// for handlers answering htmx or fetch requests with a fragment. Call it
+// before writing the body; each call adds a toast, so several can be
+// shown from one response. The header holds a JSON array of
+// {"message", "level"} objects.
+func Trigger(w http.ResponseWriter, message string, level Level) {
+	var messages []Message
+	if v := w.Header().Get(TriggerHeader); v != "" {
+		// A value set by someone else is replaced rather than corrupted
+		_ = json.Unmarshal([]byte(v), &messages)
+	}
+	messages = append(messages, Message{Text: message, Level: level})
+	b, _ := json.Marshal(messages)
+	w.Header().Set(TriggerHeader, asciiJSON(b))
+}
+
+// asciiJSON escapes non-ASCII characters in JSON text as \uXXXX, since
+// browsers decode header values as Latin-1.
+func asciiJSON(b []byte) string {
+	var sb strings.Builder
+	for _, r := range string(b) {
+		switch {
+		case r < utf8.RuneSelf:
+			sb.WriteRune(r)
+		case r > 0xFFFF:
+			r -= 0x10000
+			fmt.Fprintf(&sb, `\u%04x\u%04x`, 0xD800+(r>>10), 0xDC00+(r&0x3FF))
+		default:
+			fmt.Fprintf(&sb, `\u%04x`, r)
+		}
+	}
+	return sb.String()
+}